@@ -3,9 +3,13 @@ package policy
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/datreeio/datree/pkg/assertions"
 	"github.com/datreeio/datree/pkg/defaultPolicies"
 
 	"github.com/datreeio/datree/pkg/defaultRules"
+	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
+	extensions "github.com/datreeio/datree/pkg/jsonSchemaValidator/extensions"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 type Policy struct {
@@ -19,6 +23,42 @@ type RuleWithSchema struct {
 	DocumentationUrl string
 	Schema           interface{}
 	MessageOnFailure string
+	// IsWarning rules are reported without failing the policy check
+	IsWarning bool
+	Severity  string
+	// Categories of the default rule, used by category skip annotations
+	Categories []string
+	// Match and Exclude scope the rule to the resources they select
+	Match   *defaultPolicies.RuleSelector
+	Exclude *defaultPolicies.RuleSelector
+}
+
+// CompiledPolicy holds the policy rules together with their compiled schemas, so a schema is compiled
+// once per run instead of once per rule per resource
+type CompiledPolicy struct {
+	Policy
+	Schemas map[string]*jsonschema.Schema
+}
+
+// CompilePolicy compiles the rules schemas, the cross resource rules see the resources of the inventory
+func CompilePolicy(policy Policy, inventory *extensions.Inventory) (*CompiledPolicy, error) {
+	schemas := make(map[string]*jsonschema.Schema, len(policy.Rules))
+
+	for _, rule := range policy.Rules {
+		ruleSchemaJson, err := json.Marshal(rule.Schema)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := jsonSchemaValidator.CompileWithInventory(string(ruleSchemaJson), inventory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile rule %s: %s", rule.RuleIdentifier, err.Error())
+		}
+
+		schemas[rule.RuleIdentifier] = schema
+	}
+
+	return &CompiledPolicy{Policy: policy, Schemas: schemas}, nil
 }
 
 func CreatePolicy(policies *defaultPolicies.EvaluationPrerunPolicies, policyName string, registrationURL string, defaultRules *defaultRules.DefaultRulesDefinitions, isAnonymous bool) (Policy, error) {
@@ -58,7 +98,12 @@ func CreatePolicy(policies *defaultPolicies.EvaluationPrerunPolicies, policyName
 		}
 	}
 
-	rules, err = populateRules(chosenPolicy.Rules, policies.CustomRules, defaultRules.Rules)
+	policyRules, err := ResolvePolicyRules(policies.Policies, chosenPolicy)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	rules, err = populateRules(policyRules, policies.CustomRules, defaultRules.Rules)
 
 	if err != nil {
 		return Policy{}, err
@@ -75,28 +120,66 @@ func populateRules(policyRules []defaultPolicies.Rule, customRules []*defaultPol
 	}
 	for _, rule := range policyRules {
 		customRule := getCustomRuleByIdentifier(customRules, rule.Identifier)
+		ruleWithSchema := RuleWithSchema{RuleIdentifier: rule.Identifier, MessageOnFailure: rule.MessageOnFailure, Severity: rule.Severity, Match: rule.Match, Exclude: rule.Exclude}
 
+		// the severity of the policy rule overrides the severity of the rule definition
 		if customRule != nil {
-			if customRule.Schema == nil {
+			if len(rule.Params) > 0 {
+				return nil, fmt.Errorf("rule %s has no parameters, params are supported by default rules only", rule.Identifier)
+			}
+			ruleWithSchema.RuleName = customRule.Name
+			if ruleWithSchema.Severity == "" {
+				ruleWithSchema.Severity = customRule.Severity
+			}
+			if len(customRule.Assertions) > 0 {
+				schema, err := assertions.Compile(customRule.Assertions)
+				if err != nil {
+					return nil, fmt.Errorf("rule %s: %s", rule.Identifier, err.Error())
+				}
+				ruleWithSchema.Schema = schema
+			} else if customRule.Schema == nil {
 				schema := make(map[string]interface{})
 				err := json.Unmarshal([]byte(customRule.JsonSchema), &schema)
 				if err != nil {
 					return nil, err
 				}
-				rules = append(rules, RuleWithSchema{rule.Identifier, customRule.Name, "", schema, rule.MessageOnFailure})
+				ruleWithSchema.Schema = schema
 			} else {
-				rules = append(rules, RuleWithSchema{rule.Identifier, customRule.Name, "", customRule.Schema, rule.MessageOnFailure})
+				ruleWithSchema.Schema = customRule.Schema
 			}
 		} else {
 			defaultRule := getDefaultRuleByIdentifier(defaultRules, rule.Identifier)
 
 			if defaultRule != nil {
-				rules = append(rules, RuleWithSchema{rule.Identifier, defaultRule.Name, defaultRule.DocumentationUrl, defaultRule.Schema, rule.MessageOnFailure})
+				ruleWithSchema.RuleName = defaultRule.Name
+				ruleWithSchema.DocumentationUrl = defaultRule.DocumentationUrl
+
+				// the parameters of the rule are set in its schema and interpolated in its message
+				parametersValues, err := defaultRule.ResolveParameters(rule.Params)
+				if err != nil {
+					return nil, err
+				}
+				ruleWithSchema.Schema, err = defaultRule.GetSchemaWithParameters(parametersValues)
+				if err != nil {
+					return nil, err
+				}
+				ruleWithSchema.MessageOnFailure, err = defaultRule.InterpolateMessage(ruleWithSchema.MessageOnFailure, parametersValues)
+				if err != nil {
+					return nil, fmt.Errorf("rule %s: %s", rule.Identifier, err.Error())
+				}
+				ruleWithSchema.Categories = defaultRule.Categories
+				if ruleWithSchema.Severity == "" {
+					ruleWithSchema.Severity = defaultRule.Severity
+				}
 			} else {
 				rulesIsNotCustomNorDefaultErr := fmt.Errorf("rule %s is not custom nor default", rule.Identifier)
 				return nil, rulesIsNotCustomNorDefaultErr
 			}
 		}
+		if ruleWithSchema.Severity == "" {
+			ruleWithSchema.Severity = DefaultSeverity
+		}
+		rules = append(rules, ruleWithSchema)
 	}
 
 	return rules, nil
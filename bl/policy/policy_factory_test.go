@@ -38,9 +38,9 @@ func TestCreatePolicy(t *testing.T) {
 		for _, defaultRule := range defaultRules.Rules {
 			switch defaultRule.UniqueName {
 			case "WORKLOAD_INCORRECT_NAMESPACE_VALUE_DEFAULT":
-				expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: defaultRule.UniqueName, RuleName: defaultRule.Name, DocumentationUrl: defaultRule.DocumentationUrl, Schema: defaultRule.Schema, MessageOnFailure: "Incorrect value for key `namespace` - use an explicit namespace instead of the default one (`default`)"})
+				expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: defaultRule.UniqueName, RuleName: defaultRule.Name, DocumentationUrl: defaultRule.DocumentationUrl, Schema: defaultRule.Schema, Severity: defaultRule.Severity, Categories: defaultRule.Categories, MessageOnFailure: "Incorrect value for key `namespace` - use an explicit namespace instead of the default one (`default`)"})
 			case "CONTAINERS_INCORRECT_PRIVILEGED_VALUE_TRUE":
-				expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: defaultRule.UniqueName, RuleName: defaultRule.Name, DocumentationUrl: defaultRule.DocumentationUrl, Schema: defaultRule.Schema, MessageOnFailure: "Incorrect value for key `privileged` - this mode will allow the container thenhjgjgj same access as processes running on the host"})
+				expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: defaultRule.UniqueName, RuleName: defaultRule.Name, DocumentationUrl: defaultRule.DocumentationUrl, Schema: defaultRule.Schema, Severity: defaultRule.Severity, Categories: defaultRule.Categories, MessageOnFailure: "Incorrect value for key `privileged` - this mode will allow the container thenhjgjgj same access as processes running on the host"})
 			}
 		}
 
@@ -51,7 +51,7 @@ func TestCreatePolicy(t *testing.T) {
 			panic(err)
 		}
 
-		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "CUSTOM_WORKLOAD_INVALID_LABELS_VALUE", RuleName: "Ensure workload has valid label values [CUSTOM RULE]", Schema: customRuleJsonMap, MessageOnFailure: "All lables values must follow the RFC 1123 hostname standard (https://knowledge.broadcom.com/external/article/49542/restrictions-on-valid-host-names.html)"})
+		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "CUSTOM_WORKLOAD_INVALID_LABELS_VALUE", RuleName: "Ensure workload has valid label values [CUSTOM RULE]", Schema: customRuleJsonMap, Severity: DefaultSeverity, MessageOnFailure: "All lables values must follow the RFC 1123 hostname standard (https://knowledge.broadcom.com/external/article/49542/restrictions-on-valid-host-names.html)"})
 
 		expectedPolicy := Policy{Name: "labels_best_practices", Rules: expectedRules}
 
@@ -78,7 +78,7 @@ func TestCreatePolicy(t *testing.T) {
 			panic(err)
 		}
 
-		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "CUSTOM_WORKLOAD_INVALID_LABELS_VALUE", RuleName: "Ensure workload has valid label values [CUSTOM RULE]", Schema: customRuleJsonMap, MessageOnFailure: "All lables values must follow the RFC 1123 hostname standard (https://knowledge.broadcom.com/external/article/49542/restrictions-on-valid-host-names.html)"})
+		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "CUSTOM_WORKLOAD_INVALID_LABELS_VALUE", RuleName: "Ensure workload has valid label values [CUSTOM RULE]", Schema: customRuleJsonMap, Severity: DefaultSeverity, MessageOnFailure: "All lables values must follow the RFC 1123 hostname standard (https://knowledge.broadcom.com/external/article/49542/restrictions-on-valid-host-names.html)"})
 
 		expectedPolicy := Policy{Name: "labels_best_practices2", Rules: expectedRules}
 
@@ -103,8 +103,8 @@ func TestCreatePolicy(t *testing.T) {
 		if err != nil {
 			panic(err)
 		}
-		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "UNIQUE2", RuleName: "rule unique 2", Schema: customRuleJsonSchema, MessageOnFailure: "default message for rule fail number 2"})
-		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "UNIQUE3", RuleName: "rule unique 3", Schema: customRuleJsonSchema, MessageOnFailure: "default message for rule fail number 3"})
+		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "UNIQUE2", RuleName: "rule unique 2", Schema: customRuleJsonSchema, Severity: DefaultSeverity, MessageOnFailure: "default message for rule fail number 2"})
+		expectedRules = append(expectedRules, RuleWithSchema{RuleIdentifier: "UNIQUE3", RuleName: "rule unique 3", Schema: customRuleJsonSchema, Severity: DefaultSeverity, MessageOnFailure: "default message for rule fail number 3"})
 
 		assert.Equal(t, expectedRules, policy.Rules)
 	})
@@ -151,3 +151,46 @@ func mockGetPreRunData() *cliClient.EvaluationPrerunDataResponse {
 	}
 	return policiesJson
 }
+
+func TestPopulateRulesParams(t *testing.T) {
+	defaultRuleDefinitions := []*defaultRules.DefaultRuleDefinition{{
+		UniqueName: "DEFAULT_RULE",
+		Schema:     map[string]interface{}{"properties": map[string]interface{}{"replicas": map[string]interface{}{"minimum": float64(2)}}},
+		Parameters: []*defaultRules.RuleParameter{
+			{Name: "minReplicas", Type: defaultRules.ParameterTypeInteger, Default: float64(2), SchemaPaths: []string{"/properties/replicas/minimum"}},
+		},
+	}}
+	customRules := []*defaultPolicies.CustomRule{{Identifier: "CUSTOM_RULE", Schema: map[string]interface{}{}}}
+
+	rules, err := populateRules([]defaultPolicies.Rule{
+		{Identifier: "DEFAULT_RULE", MessageOnFailure: "run {{ .minReplicas }} replicas", Params: map[string]interface{}{"minReplicas": float64(3)}},
+	}, customRules, defaultRuleDefinitions)
+	assert.Nil(t, err)
+	assert.Equal(t, "run 3 replicas", rules[0].MessageOnFailure)
+	assert.Equal(t, map[string]interface{}{"properties": map[string]interface{}{"replicas": map[string]interface{}{"minimum": float64(3)}}}, rules[0].Schema)
+
+	rules, err = populateRules([]defaultPolicies.Rule{{Identifier: "DEFAULT_RULE", MessageOnFailure: "run {{ .minReplicas }} replicas"}}, customRules, defaultRuleDefinitions)
+	assert.Nil(t, err)
+	assert.Equal(t, "run 2 replicas", rules[0].MessageOnFailure)
+
+	_, err = populateRules([]defaultPolicies.Rule{{Identifier: "CUSTOM_RULE", Params: map[string]interface{}{"minReplicas": float64(3)}}}, customRules, defaultRuleDefinitions)
+	assert.EqualError(t, err, "rule CUSTOM_RULE has no parameters, params are supported by default rules only")
+}
+
+func TestPopulateRulesAssertions(t *testing.T) {
+	customRules := []*defaultPolicies.CustomRule{{
+		Identifier: "CUSTOM_RULE",
+		Name:       "custom rule",
+		Assertions: []defaultPolicies.Assertion{{Path: "spec.replicas", Op: "gte", Value: float64(2)}},
+	}}
+
+	rules, err := populateRules([]defaultPolicies.Rule{{Identifier: "CUSTOM_RULE", MessageOnFailure: "run 2 replicas or more"}}, customRules, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"allOf": []interface{}{
+		map[string]interface{}{"properties": map[string]interface{}{"spec": map[string]interface{}{"properties": map[string]interface{}{"replicas": map[string]interface{}{"minimum": float64(2)}}}}},
+	}}, rules[0].Schema)
+
+	customRules[0].Assertions[0].Op = "gt"
+	_, err = populateRules([]defaultPolicies.Rule{{Identifier: "CUSTOM_RULE"}}, customRules, nil)
+	assert.EqualError(t, err, "rule CUSTOM_RULE: assertion 0: unknown op \"gt\", valid ops are exists, notExists, eq, in, matches, gte, lte, quantityGte, quantityLte")
+}
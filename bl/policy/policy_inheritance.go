@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"k8s.io/utils/strings/slices"
+)
+
+// ResolvePolicyRules returns the rules of the policy with the rules of the policies it extends.
+// The rules of later parents override the rules of earlier parents and the rules of the policy override both,
+// then the disabled rules are removed and the message overrides are applied
+func ResolvePolicyRules(policies []*defaultPolicies.Policy, policy *defaultPolicies.Policy) ([]defaultPolicies.Rule, error) {
+	return resolvePolicyRules(policies, policy, []string{})
+}
+
+func resolvePolicyRules(policies []*defaultPolicies.Policy, policy *defaultPolicies.Policy, inheritancePath []string) ([]defaultPolicies.Rule, error) {
+	inheritancePath = append(inheritancePath, policy.Name)
+	if slices.Contains(inheritancePath[:len(inheritancePath)-1], policy.Name) {
+		return nil, fmt.Errorf("policies inheritance cycle: %s", strings.Join(inheritancePath, " -> "))
+	}
+
+	var rules []defaultPolicies.Rule
+	for _, parentName := range policy.Extends {
+		parent := getPolicyByName(policies, parentName)
+		if parent == nil {
+			return nil, fmt.Errorf("policy %s extends policy %s that doesn't exist", policy.Name, parentName)
+		}
+
+		parentRules, err := resolvePolicyRules(policies, parent, inheritancePath)
+		if err != nil {
+			return nil, err
+		}
+		rules = mergeRules(rules, parentRules)
+	}
+	rules = mergeRules(rules, policy.Rules)
+
+	var resolvedRules []defaultPolicies.Rule
+	for _, rule := range rules {
+		if slices.Contains(policy.Disable, rule.Identifier) {
+			continue
+		}
+		if message, ok := policy.MessageOverrides[rule.Identifier]; ok {
+			rule.MessageOnFailure = message
+		}
+		resolvedRules = append(resolvedRules, rule)
+	}
+
+	return resolvedRules, nil
+}
+
+// mergeRules returns the rules with the overriding rules, an overriding rule keeps the position of the rule it overrides
+func mergeRules(rules []defaultPolicies.Rule, overridingRules []defaultPolicies.Rule) []defaultPolicies.Rule {
+	mergedRules := append([]defaultPolicies.Rule{}, rules...)
+	for _, overridingRule := range overridingRules {
+		overridden := false
+		for index := range mergedRules {
+			if mergedRules[index].Identifier == overridingRule.Identifier {
+				mergedRules[index] = overridingRule
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			mergedRules = append(mergedRules, overridingRule)
+		}
+	}
+	return mergedRules
+}
+
+func getPolicyByName(policies []*defaultPolicies.Policy, name string) *defaultPolicies.Policy {
+	for _, policy := range policies {
+		if policy.Name == name {
+			return policy
+		}
+	}
+	return nil
+}
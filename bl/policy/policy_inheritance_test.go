@@ -0,0 +1,70 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePolicyRules(t *testing.T) {
+	base := &defaultPolicies.Policy{
+		Name: "Base",
+		Rules: []defaultPolicies.Rule{
+			{Identifier: "RULE_A", MessageOnFailure: "base message a"},
+			{Identifier: "RULE_B", MessageOnFailure: "base message b"},
+			{Identifier: "RULE_C", MessageOnFailure: "base message c"},
+		},
+	}
+	security := &defaultPolicies.Policy{
+		Name: "Security",
+		Rules: []defaultPolicies.Rule{
+			{Identifier: "RULE_B", MessageOnFailure: "security message b", Severity: SeverityCritical},
+			{Identifier: "RULE_D", MessageOnFailure: "security message d"},
+		},
+	}
+	production := &defaultPolicies.Policy{
+		Name:             "Production",
+		Extends:          []string{"Base", "Security"},
+		Disable:          []string{"RULE_C"},
+		MessageOverrides: map[string]string{"RULE_A": "production message a"},
+		Rules: []defaultPolicies.Rule{
+			{Identifier: "RULE_D", MessageOnFailure: "production message d"},
+			{Identifier: "RULE_E", MessageOnFailure: "production message e"},
+		},
+	}
+	policies := []*defaultPolicies.Policy{base, security, production}
+
+	t.Run("policy without extends", func(t *testing.T) {
+		rules, err := ResolvePolicyRules(policies, base)
+		assert.Nil(t, err)
+		assert.Equal(t, base.Rules, rules)
+	})
+
+	t.Run("policy with extends, disable and message overrides", func(t *testing.T) {
+		rules, err := ResolvePolicyRules(policies, production)
+		assert.Nil(t, err)
+		assert.Equal(t, []defaultPolicies.Rule{
+			{Identifier: "RULE_A", MessageOnFailure: "production message a"},
+			{Identifier: "RULE_B", MessageOnFailure: "security message b", Severity: SeverityCritical},
+			{Identifier: "RULE_D", MessageOnFailure: "production message d"},
+			{Identifier: "RULE_E", MessageOnFailure: "production message e"},
+		}, rules)
+		// resolving doesn't change the rules of the parents
+		assert.Equal(t, "base message a", base.Rules[0].MessageOnFailure)
+	})
+
+	t.Run("policy that extends a missing policy", func(t *testing.T) {
+		orphan := &defaultPolicies.Policy{Name: "Orphan", Extends: []string{"Missing"}}
+		_, err := ResolvePolicyRules(append(policies, orphan), orphan)
+		assert.Equal(t, errors.New("policy Orphan extends policy Missing that doesn't exist"), err)
+	})
+
+	t.Run("policies inheritance cycle", func(t *testing.T) {
+		first := &defaultPolicies.Policy{Name: "First", Extends: []string{"Second"}}
+		second := &defaultPolicies.Policy{Name: "Second", Extends: []string{"Base", "First"}}
+		_, err := ResolvePolicyRules(append(policies, first, second), first)
+		assert.Equal(t, errors.New("policies inheritance cycle: First -> Second -> First"), err)
+	})
+}
@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"k8s.io/utils/strings/slices"
+)
+
+// resources without a namespace are in the default namespace, as kubectl would apply them
+const defaultNamespace = "default"
+
+// SelectedResource holds the properties of a resource that the match and exclude blocks of a rule select by
+type SelectedResource struct {
+	Kind        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+	// FilePaths are the path of the resource's file as given and relative to the working directory
+	FilePaths []string
+}
+
+func NewSelectedResource(resource interface{}, filePath string) SelectedResource {
+	resourceObject, _ := resource.(map[string]interface{})
+	metadata, _ := resourceObject["metadata"].(map[string]interface{})
+
+	selectedResource := SelectedResource{
+		Labels:      getStringsMap(metadata["labels"]),
+		Annotations: getStringsMap(metadata["annotations"]),
+		FilePaths:   []string{filepath.ToSlash(filePath)},
+	}
+	selectedResource.Kind, _ = resourceObject["kind"].(string)
+	selectedResource.Namespace, _ = metadata["namespace"].(string)
+	if selectedResource.Namespace == "" {
+		selectedResource.Namespace = defaultNamespace
+	}
+
+	if workingDirectory, err := os.Getwd(); err == nil && filepath.IsAbs(filePath) {
+		if relativePath, err := filepath.Rel(workingDirectory, filePath); err == nil {
+			selectedResource.FilePaths = append(selectedResource.FilePaths, filepath.ToSlash(relativePath))
+		}
+	}
+
+	return selectedResource
+}
+
+// AppliesTo returns whether the rule should be evaluated on the resource, according to its match and exclude blocks
+func (rule RuleWithSchema) AppliesTo(resource SelectedResource) bool {
+	if rule.Match != nil && !isSelected(rule.Match, resource) {
+		return false
+	}
+	if rule.Exclude != nil && isSelected(rule.Exclude, resource) {
+		return false
+	}
+	return true
+}
+
+func isSelected(selector *defaultPolicies.RuleSelector, resource SelectedResource) bool {
+	if len(selector.Kinds) > 0 && !slices.Contains(selector.Kinds, resource.Kind) {
+		return false
+	}
+	if len(selector.Namespaces) > 0 && !matchesAnyGlob(selector.Namespaces, []string{resource.Namespace}) {
+		return false
+	}
+	if selector.Labels != nil && !isLabelSelectorMatched(selector.Labels, resource.Labels) {
+		return false
+	}
+	if selector.Annotations != nil && !isLabelSelectorMatched(selector.Annotations, resource.Annotations) {
+		return false
+	}
+	if len(selector.Files) > 0 && !matchesAnyGlob(selector.Files, resource.FilePaths) {
+		return false
+	}
+	return true
+}
+
+func isLabelSelectorMatched(selector *defaultPolicies.LabelSelector, labels map[string]string) bool {
+	for key, value := range selector.MatchLabels {
+		if labelValue, ok := labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+
+	for _, requirement := range selector.MatchExpressions {
+		labelValue, ok := labels[requirement.Key]
+		switch requirement.Operator {
+		case "In":
+			if !ok || !slices.Contains(requirement.Values, labelValue) {
+				return false
+			}
+		case "NotIn":
+			if ok && slices.Contains(requirement.Values, labelValue) {
+				return false
+			}
+		case "Exists":
+			if !ok {
+				return false
+			}
+		case "DoesNotExist":
+			if ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAnyGlob supports ** to match any number of directories
+func matchesAnyGlob(globs []string, values []string) bool {
+	for _, glob := range globs {
+		for _, value := range values {
+			if matched, _ := doublestar.Match(glob, value); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getStringsMap(value interface{}) map[string]string {
+	valuesMap, _ := value.(map[string]interface{})
+	stringsMap := make(map[string]string, len(valuesMap))
+	for key, value := range valuesMap {
+		if stringValue, ok := value.(string); ok {
+			stringsMap[key] = stringValue
+		}
+	}
+	return stringsMap
+}
@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/defaultRules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSelectedResource(t *testing.T) {
+	resource := map[string]interface{}{
+		"kind": "Deployment",
+		"metadata": map[string]interface{}{
+			"name":        "web",
+			"labels":      map[string]interface{}{"team": "payments", "replicas": 3},
+			"annotations": map[string]interface{}{"example.com/owner": "payments"},
+		},
+	}
+
+	selectedResource := NewSelectedResource(resource, "manifests/web.yaml")
+	assert.Equal(t, SelectedResource{
+		Kind:        "Deployment",
+		Namespace:   "default",
+		Labels:      map[string]string{"team": "payments"},
+		Annotations: map[string]string{"example.com/owner": "payments"},
+		FilePaths:   []string{"manifests/web.yaml"},
+	}, selectedResource)
+}
+
+func TestAppliesTo(t *testing.T) {
+	resource := SelectedResource{
+		Kind:        "Deployment",
+		Namespace:   "prod-payments",
+		Labels:      map[string]string{"team": "payments", "tier": "backend"},
+		Annotations: map[string]string{"example.com/legacy": "true"},
+		FilePaths:   []string{"manifests/prod/web.yaml"},
+	}
+
+	tests := []struct {
+		name     string
+		match    *defaultPolicies.RuleSelector
+		exclude  *defaultPolicies.RuleSelector
+		expected bool
+	}{
+		{name: "no selectors", expected: true},
+		{name: "matching kind", match: &defaultPolicies.RuleSelector{Kinds: []string{"StatefulSet", "Deployment"}}, expected: true},
+		{name: "other kind", match: &defaultPolicies.RuleSelector{Kinds: []string{"StatefulSet"}}, expected: false},
+		{name: "matching namespace glob", match: &defaultPolicies.RuleSelector{Namespaces: []string{"prod-*"}}, expected: true},
+		{name: "kind matches but namespace does not", match: &defaultPolicies.RuleSelector{Kinds: []string{"Deployment"}, Namespaces: []string{"staging"}}, expected: false},
+		{name: "matching labels", match: &defaultPolicies.RuleSelector{Labels: &defaultPolicies.LabelSelector{
+			MatchLabels: map[string]string{"team": "payments"},
+			MatchExpressions: []defaultPolicies.LabelSelectorRequirement{
+				{Key: "tier", Operator: "In", Values: []string{"backend", "frontend"}},
+				{Key: "deprecated", Operator: "DoesNotExist"},
+			},
+		}}, expected: true},
+		{name: "label with other value", match: &defaultPolicies.RuleSelector{Labels: &defaultPolicies.LabelSelector{
+			MatchExpressions: []defaultPolicies.LabelSelectorRequirement{{Key: "tier", Operator: "NotIn", Values: []string{"backend"}}},
+		}}, expected: false},
+		{name: "file glob", match: &defaultPolicies.RuleSelector{Files: []string{"manifests/**/*.yaml"}}, expected: true},
+		{name: "excluded annotation", exclude: &defaultPolicies.RuleSelector{Annotations: &defaultPolicies.LabelSelector{
+			MatchExpressions: []defaultPolicies.LabelSelectorRequirement{{Key: "example.com/legacy", Operator: "Exists"}},
+		}}, expected: false},
+		{name: "excluded file", match: &defaultPolicies.RuleSelector{Kinds: []string{"Deployment"}}, exclude: &defaultPolicies.RuleSelector{Files: []string{"manifests/prod/**"}}, expected: false},
+		{name: "exclude of other namespace", exclude: &defaultPolicies.RuleSelector{Namespaces: []string{"kube-system"}}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := RuleWithSchema{Match: tt.match, Exclude: tt.exclude}
+			assert.Equal(t, tt.expected, rule.AppliesTo(resource))
+		})
+	}
+}
+
+func TestPopulateRulesWithSelectors(t *testing.T) {
+	match := &defaultPolicies.RuleSelector{Kinds: []string{"Deployment"}}
+	exclude := &defaultPolicies.RuleSelector{Namespaces: []string{"kube-system"}}
+	policyRules := []defaultPolicies.Rule{{Identifier: "CUSTOM_RULE", MessageOnFailure: "failed", Match: match, Exclude: exclude}}
+	customRules := []*defaultPolicies.CustomRule{{Identifier: "CUSTOM_RULE", Name: "custom rule", Schema: map[string]interface{}{}}}
+
+	rules, err := populateRules(policyRules, customRules, []*defaultRules.DefaultRuleDefinition{})
+	assert.Nil(t, err)
+	assert.Equal(t, match, rules[0].Match)
+	assert.Equal(t, exclude, rules[0].Exclude)
+}
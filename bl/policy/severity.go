@@ -0,0 +1,31 @@
+package policy
+
+import "k8s.io/utils/strings/slices"
+
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// Severities are ordered from the most to the least severe
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// DefaultSeverity is the severity of rules that don't define one
+const DefaultSeverity = SeverityMedium
+
+func IsValidSeverity(severity string) bool {
+	return slices.Contains(Severities, severity)
+}
+
+// IsSeverityAtLeast returns whether the severity is as severe as the threshold or more, an empty severity is the default severity
+func IsSeverityAtLeast(severity string, threshold string) bool {
+	if severity == "" {
+		severity = DefaultSeverity
+	}
+	severityIndex := slices.Index(Severities, severity)
+	thresholdIndex := slices.Index(Severities, threshold)
+	return severityIndex != -1 && thresholdIndex != -1 && severityIndex <= thresholdIndex
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/defaultRules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSeverityAtLeast(t *testing.T) {
+	assert.True(t, IsSeverityAtLeast(SeverityCritical, SeverityHigh))
+	assert.True(t, IsSeverityAtLeast(SeverityHigh, SeverityHigh))
+	assert.False(t, IsSeverityAtLeast(SeverityMedium, SeverityHigh))
+	assert.True(t, IsSeverityAtLeast("", SeverityMedium))
+	assert.False(t, IsSeverityAtLeast("", SeverityHigh))
+	assert.False(t, IsSeverityAtLeast("unknown", SeverityInfo))
+}
+
+func TestPopulateRulesSeverity(t *testing.T) {
+	policyRules := []defaultPolicies.Rule{
+		{Identifier: "DEFAULT_RULE"},
+		{Identifier: "DEFAULT_RULE_WITH_OVERRIDE", Severity: SeverityCritical},
+		{Identifier: "CUSTOM_RULE"},
+		{Identifier: "CUSTOM_RULE_WITHOUT_SEVERITY"},
+	}
+	customRules := []*defaultPolicies.CustomRule{
+		{Identifier: "CUSTOM_RULE", Schema: map[string]interface{}{}, Severity: SeverityLow},
+		{Identifier: "CUSTOM_RULE_WITHOUT_SEVERITY", Schema: map[string]interface{}{}},
+	}
+	defaultRuleDefinitions := []*defaultRules.DefaultRuleDefinition{
+		{UniqueName: "DEFAULT_RULE", Severity: SeverityHigh},
+		{UniqueName: "DEFAULT_RULE_WITH_OVERRIDE", Severity: SeverityInfo},
+	}
+
+	rules, err := populateRules(policyRules, customRules, defaultRuleDefinitions)
+	assert.Nil(t, err)
+
+	var severities []string
+	for _, rule := range rules {
+		severities = append(severities, rule.Severity)
+	}
+	assert.Equal(t, []string{SeverityHigh, SeverityCritical, SeverityLow, DefaultSeverity}, severities)
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var ConfigAvailableKeys = []string{"token", "offline", "policy_config", "schema_locations"}
+var ConfigAvailableKeys = []string{"token", "offline", "policy_config", "schema_locations", "exceptions"}
 
 type Messager interface {
 	LoadVersionMessages(cliVersion string) chan *messager.VersionMessage
@@ -0,0 +1,128 @@
+package exceptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/datreeio/datree/cmd/test"
+	"github.com/datreeio/datree/pkg/evaluation"
+	"github.com/datreeio/datree/pkg/exceptions"
+	"github.com/datreeio/datree/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func New(testCtx *test.TestCommandContext) *cobra.Command {
+	testCommandFlags := test.NewTestCommandFlags()
+	reportCommand := &cobra.Command{
+		Use:   "report <pattern>",
+		Short: "List the active, expired and unused exceptions of the exceptions file for given <pattern>",
+		Long:  "Run the policy check for given <pattern> and list the exceptions of the exceptions file by status: active exceptions skipped failures, expired exceptions no longer skip failures and unused exceptions didn't skip any failure",
+		Example: utils.Example(`
+		# Report the exceptions of the exceptions file set in the datree config.yaml file
+		datree exceptions report kube-prod/*.yaml
+
+		# Report the exceptions of a given exceptions file
+		datree exceptions report kube-prod/*.yaml --exceptions exceptions.yaml
+		`),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("requires at least 1 arg(s), only received 0")
+			}
+			if testCommandFlags.Output != "" && testCommandFlags.Output != "json" {
+				return fmt.Errorf("invalid --output option - %q\nValid output values are - json", testCommandFlags.Output)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			test.SetSilentMode(cmd)
+			var err error = nil
+			defer func() {
+				if err != nil {
+					testCtx.Printer.PrintError(strings.Join([]string{"\n", err.Error(), "\n"}, ""), "error")
+				}
+			}()
+
+			// the report always runs the policy check, so the output flag only applies to the report
+			output := testCommandFlags.Output
+			testCommandFlags.Output = "json"
+
+			testCommandData, evaluationResultData, err := test.EvaluateWrapper(testCtx, args, testCommandFlags)
+			if err != nil {
+				return err
+			}
+			if testCommandData.Exceptions == nil {
+				err = fmt.Errorf("no exceptions file to report on, use --exceptions <file> or `datree config set exceptions <file>`")
+				return err
+			}
+
+			report := testCommandData.Exceptions.Report(countSkippedFailuresByException(evaluationResultData.FormattedResults.EvaluationResults))
+			if output == "json" {
+				reportJson, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				testCtx.Printer.PrintMessage(string(reportJson)+"\n", "")
+				return nil
+			}
+
+			printReport(testCtx.Printer, report, testCommandData.ExceptionsPath)
+			return nil
+		},
+	}
+	testCommandFlags.AddFlags(reportCommand)
+
+	exceptionsCommand := &cobra.Command{
+		Use:   "exceptions",
+		Short: "Review the exceptions of the exceptions file",
+	}
+
+	exceptionsCommand.AddCommand(reportCommand)
+
+	return exceptionsCommand
+}
+
+// countSkippedFailuresByException counts the failures that each exception skipped in the run
+func countSkippedFailuresByException(evaluationResults *evaluation.EvaluationResults) map[*exceptions.Exception]int {
+	skippedFailuresByException := make(map[*exceptions.Exception]int)
+	if evaluationResults == nil {
+		return skippedFailuresByException
+	}
+
+	for _, rules := range evaluationResults.FileNameRuleMapper {
+		for _, rule := range rules {
+			for _, occurrenceDetails := range rule.OccurrencesDetails {
+				if occurrenceDetails.Exception != nil {
+					skippedFailuresByException[occurrenceDetails.Exception] += occurrenceDetails.Occurrences
+				}
+			}
+		}
+	}
+	return skippedFailuresByException
+}
+
+type Printer interface {
+	PrintMessage(messageText string, messageColor string)
+}
+
+func printReport(printer Printer, report []exceptions.ReportEntry, exceptionsPath string) {
+	printer.PrintMessage(fmt.Sprintf("Exceptions of %s\n", exceptionsPath), "")
+
+	for _, status := range []struct{ name, color string }{{exceptions.StatusActive, "green"}, {exceptions.StatusExpired, "red"}, {exceptions.StatusUnused, "yellow"}} {
+		var entries []exceptions.ReportEntry
+		for _, entry := range report {
+			if entry.Status == status.name {
+				entries = append(entries, entry)
+			}
+		}
+
+		printer.PrintMessage(fmt.Sprintf("\n%s (%d)\n", strings.ToUpper(status.name), len(entries)), status.color)
+		for _, entry := range entries {
+			text := "- " + entry.Exception.Description()
+			if entry.Status == exceptions.StatusActive {
+				text += fmt.Sprintf(" - skipped %d failures", entry.SkippedCount)
+			}
+			printer.PrintMessage(text+"\n", "")
+		}
+	}
+}
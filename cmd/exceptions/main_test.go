@@ -0,0 +1,154 @@
+package exceptions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/datreeio/datree/cmd/test"
+	"github.com/datreeio/datree/pkg/evaluation"
+	"github.com/datreeio/datree/pkg/exceptions"
+	"github.com/datreeio/datree/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type printerMock struct {
+	mock.Mock
+}
+
+func (p *printerMock) PrintMessage(messageText string, messageColor string) {
+	p.Called(messageText, messageColor)
+}
+
+// printedOutput returns the messages that were printed, each prefixed by its color when it has one
+func (p *printerMock) printedOutput() string {
+	var output strings.Builder
+	for _, call := range p.Calls {
+		if color := call.Arguments.String(1); color != "" {
+			output.WriteString("<" + color + ">")
+		}
+		output.WriteString(call.Arguments.String(0))
+	}
+	return output.String()
+}
+
+func TestCountSkippedFailuresByException(t *testing.T) {
+	productionException := &exceptions.Exception{Rule: "CONTAINERS_MISSING_IMAGE_VALUE_VERSION", Namespace: "prod-*", Owner: "platform", Reason: "pinned by digest"}
+	legacyException := &exceptions.Exception{Rule: "*", File: "legacy/**", Owner: "legacy", Reason: "migrating"}
+
+	tests := []struct {
+		name               string
+		evaluationResults  *evaluation.EvaluationResults
+		expectedSkipCounts map[*exceptions.Exception]int
+	}{
+		{
+			name:               "no evaluation results",
+			evaluationResults:  nil,
+			expectedSkipCounts: map[*exceptions.Exception]int{},
+		},
+		{
+			name: "failures that no exception skipped",
+			evaluationResults: &evaluation.EvaluationResults{FileNameRuleMapper: evaluation.FileNameRuleMapper{
+				"deployment.yaml": {"CONTAINERS_MISSING_IMAGE_VALUE_VERSION": {OccurrencesDetails: []evaluation.OccurrenceDetails{{MetadataName: "web", Occurrences: 2}}}},
+			}},
+			expectedSkipCounts: map[*exceptions.Exception]int{},
+		},
+		{
+			name: "failures skipped by exceptions across files and rules",
+			evaluationResults: &evaluation.EvaluationResults{FileNameRuleMapper: evaluation.FileNameRuleMapper{
+				"prod/deployment.yaml": {
+					"CONTAINERS_MISSING_IMAGE_VALUE_VERSION": {OccurrencesDetails: []evaluation.OccurrenceDetails{
+						{MetadataName: "web", Occurrences: 2, IsSkipped: true, Exception: productionException},
+						{MetadataName: "api", Occurrences: 1},
+					}},
+				},
+				"prod/worker.yaml": {
+					"CONTAINERS_MISSING_IMAGE_VALUE_VERSION": {OccurrencesDetails: []evaluation.OccurrenceDetails{{MetadataName: "worker", Occurrences: 1, IsSkipped: true, Exception: productionException}}},
+				},
+				"legacy/cronjob.yaml": {
+					"CONTAINERS_MISSING_MEMORY_LIMIT_KEY": {OccurrencesDetails: []evaluation.OccurrenceDetails{{MetadataName: "cleanup", Occurrences: 3, IsSkipped: true, Exception: legacyException}}},
+					"CONTAINERS_MISSING_CPU_LIMIT_KEY":    {OccurrencesDetails: []evaluation.OccurrenceDetails{{MetadataName: "cleanup", Occurrences: 1, IsSkipped: true, Exception: legacyException}}},
+				},
+			}},
+			expectedSkipCounts: map[*exceptions.Exception]int{productionException: 3, legacyException: 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedSkipCounts, countSkippedFailuresByException(tt.evaluationResults))
+		})
+	}
+}
+
+func TestPrintReport(t *testing.T) {
+	activeException := &exceptions.Exception{Rule: "CONTAINERS_MISSING_IMAGE_VALUE_VERSION", Namespace: "prod-*", Owner: "platform", Ticket: "OPS-12", Reason: "pinned by digest"}
+	expiredException := &exceptions.Exception{Rule: "CONTAINERS_MISSING_MEMORY_LIMIT_KEY", Kind: "CronJob", Owner: "data", Reason: "batch jobs", Expires: "2020-01-01"}
+	unusedException := &exceptions.Exception{Rule: "*", Owner: "legacy", Reason: "migrating"}
+
+	tests := []struct {
+		name           string
+		report         []exceptions.ReportEntry
+		expectedOutput string
+	}{
+		{
+			name:   "no exceptions",
+			report: nil,
+			expectedOutput: "Exceptions of exceptions.yaml\n" +
+				"<green>\nACTIVE (0)\n" +
+				"<red>\nEXPIRED (0)\n" +
+				"<yellow>\nUNUSED (0)\n",
+		},
+		{
+			name: "active, expired and unused exceptions",
+			report: []exceptions.ReportEntry{
+				{Exception: unusedException, Status: exceptions.StatusUnused},
+				{Exception: activeException, Status: exceptions.StatusActive, SkippedCount: 3},
+				{Exception: expiredException, Status: exceptions.StatusExpired},
+			},
+			expectedOutput: "Exceptions of exceptions.yaml\n" +
+				"<green>\nACTIVE (1)\n" +
+				"- CONTAINERS_MISSING_IMAGE_VALUE_VERSION [namespace: prod-*] (owner: platform, ticket: OPS-12) - skipped 3 failures\n" +
+				"<red>\nEXPIRED (1)\n" +
+				"- CONTAINERS_MISSING_MEMORY_LIMIT_KEY [kind: CronJob] (owner: data, expires: 2020-01-01)\n" +
+				"<yellow>\nUNUSED (1)\n" +
+				"- * [all resources] (owner: legacy)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reportPrinter := &printerMock{}
+			reportPrinter.On("PrintMessage", mock.Anything, mock.Anything)
+
+			printReport(reportPrinter, tt.report, "exceptions.yaml")
+
+			assert.Equal(t, tt.expectedOutput, reportPrinter.printedOutput())
+		})
+	}
+}
+
+func TestReportCommandArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		arguments     []string
+		expectedError error
+	}{
+		{name: "default output", arguments: []string{"manifests/*.yaml"}, expectedError: nil},
+		{name: "json output", arguments: []string{"manifests/*.yaml", "--output", "json"}, expectedError: nil},
+		{name: "unsupported output", arguments: []string{"manifests/*.yaml", "--output", "yaml"}, expectedError: errors.New("invalid --output option - \"yaml\"\nValid output values are - json")},
+		{name: "no pattern", arguments: []string{}, expectedError: errors.New("requires at least 1 arg(s), only received 0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reportCommand := New(&test.TestCommandContext{Printer: printer.CreateNewPrinter()}).Commands()[0]
+			err := reportCommand.ParseFlags(tt.arguments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = reportCommand.Args(reportCommand, reportCommand.Flags().Args())
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
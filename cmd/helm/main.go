@@ -0,0 +1,141 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/datreeio/datree/cmd/test"
+	"github.com/datreeio/datree/pkg/renderer"
+	"github.com/datreeio/datree/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type HelmCommandRunner interface {
+	ExecuteHelmBin(args []string) ([]byte, error)
+	CreateTempFile(tempFilePrefix string, content []byte) (string, error)
+}
+
+type HelmContext struct {
+	CommandRunner HelmCommandRunner
+}
+
+// HelmTemplateFlags are the flags that are passed to helm template when the chart is rendered
+type HelmTemplateFlags struct {
+	ValueFiles  []string
+	Values      []string
+	Namespace   string
+	KubeVersion string
+}
+
+func (flags *HelmTemplateFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&flags.ValueFiles, "values", "f", []string{}, "Specify values in a YAML file (can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&flags.Values, "set", []string{}, "Set values on the command line, for example key1=val1,key2=val2 (can be specified multiple times)")
+	cmd.Flags().StringVarP(&flags.Namespace, "namespace", "n", "", "Namespace to render the chart in")
+	cmd.Flags().StringVar(&flags.KubeVersion, "kube-version", "", "Kubernetes version used for Capabilities.KubeVersion when the chart is rendered. Also used as the schema-version when it isn't set")
+}
+
+// BuildHelmTemplateArgs returns the args of helm template for the chart
+func BuildHelmTemplateArgs(chart string, flags *HelmTemplateFlags) []string {
+	args := []string{chart}
+	for _, valueFile := range flags.ValueFiles {
+		args = append(args, "--values", valueFile)
+	}
+	for _, value := range flags.Values {
+		args = append(args, "--set", value)
+	}
+	if flags.Namespace != "" {
+		args = append(args, "--namespace", flags.Namespace)
+	}
+	if flags.KubeVersion != "" {
+		args = append(args, "--kube-version", flags.KubeVersion)
+	}
+	return args
+}
+
+// getSchemaVersion returns the schema version of a helm kube version, or an empty string when it isn't a full <MAJOR>.<MINOR>.<PATCH> version
+func getSchemaVersion(kubeVersion string) string {
+	schemaVersion := strings.TrimPrefix(kubeVersion, "v")
+	if isFullVersion, _ := regexp.MatchString(`^[0-9]+\.[0-9]+\.[0-9]+$`, schemaVersion); isFullVersion {
+		return schemaVersion
+	}
+	return ""
+}
+
+func New(testCtx *test.TestCommandContext, helmCtx *HelmContext) *cobra.Command {
+	testCommandFlags := test.NewTestCommandFlags()
+	helmTemplateFlags := &HelmTemplateFlags{}
+	helmTestCommand := &cobra.Command{
+		Use:   "test <chart>",
+		Short: "Execute datree test for helm template <chart>",
+		Long:  "Execute datree test for helm template <chart>. Input should be a chart directory, a packaged chart or a chart reference of a helm repository.",
+		Example: utils.Example(`
+		# Test the chart in the current directory
+		datree helm test .
+
+		# Test the chart with the values of production
+		datree helm test ./charts/api --values ./charts/api/values-production.yaml --set image.tag=1.2.3 --namespace api
+
+		# Test the chart as rendered for a kubernetes version
+		datree helm test ./charts/api --kube-version 1.27.0
+		`),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("accepts 1 arg(s), received %d", len(args))
+			}
+			if testCommandFlags.K8sVersion == "" {
+				testCommandFlags.K8sVersion = getSchemaVersion(helmTemplateFlags.KubeVersion)
+			}
+			return testCommandFlags.Validate()
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return test.LoadVersionMessages(testCtx, args, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			test.SetSilentMode(cmd)
+			var err error = nil
+			defer func() {
+				if err != nil {
+					testCtx.Printer.PrintError(strings.Join([]string{"\n", err.Error(), "\n"}, ""), "error")
+				}
+			}()
+
+			out, err := helmCtx.CommandRunner.ExecuteHelmBin(BuildHelmTemplateArgs(args[0], helmTemplateFlags))
+			if err != nil {
+				return err
+			}
+
+			// the results of a local chart cite its template files, like the results of the charts found by datree test
+			if chartInfo, statErr := os.Stat(args[0]); statErr == nil && chartInfo.IsDir() {
+				out = renderer.SetChartSources(out, args[0])
+			}
+
+			tempFilename, err := helmCtx.CommandRunner.CreateTempFile("datree_helm", out)
+			if err != nil {
+				return err
+			}
+
+			if !testCommandFlags.SaveRendered {
+				defer os.Remove(tempFilename)
+			}
+
+			err = test.TestWrapper(testCtx, []string{tempFilename}, testCommandFlags)
+			if err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+	testCommandFlags.AddFlags(helmTestCommand)
+	helmTemplateFlags.AddFlags(helmTestCommand)
+
+	helmCommand := &cobra.Command{
+		Use:   "helm",
+		Short: "Render a helm chart and run a policy check against its resources",
+	}
+
+	helmCommand.AddCommand(helmTestCommand)
+
+	return helmCommand
+}
@@ -0,0 +1,113 @@
+package helm
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/datreeio/datree/cmd/test"
+	"github.com/datreeio/datree/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type helmCommandRunnerMock struct {
+	mock.Mock
+}
+
+func (c *helmCommandRunnerMock) ExecuteHelmBin(args []string) ([]byte, error) {
+	arguments := c.Called(args)
+	return arguments.Get(0).([]byte), arguments.Error(1)
+}
+
+func (c *helmCommandRunnerMock) CreateTempFile(tempFilePrefix string, content []byte) (string, error) {
+	arguments := c.Called(tempFilePrefix, content)
+	return arguments.String(0), arguments.Error(1)
+}
+
+// runHelmTestCommand runs the helm test command without its pre run, which loads the version messages from the backend
+func runHelmTestCommand(commandRunner HelmCommandRunner, arguments []string) error {
+	helmTestCommand := New(&test.TestCommandContext{Printer: printer.CreateNewPrinter()}, &HelmContext{CommandRunner: commandRunner}).Commands()[0]
+	err := helmTestCommand.ParseFlags(arguments)
+	if err != nil {
+		return err
+	}
+	args := helmTestCommand.Flags().Args()
+	err = helmTestCommand.Args(helmTestCommand, args)
+	if err != nil {
+		return err
+	}
+	return helmTestCommand.RunE(helmTestCommand, args)
+}
+
+const renderedChart = "---\n# Source: api/templates/deployment.yaml\napiVersion: apps/v1\nkind: Deployment\n"
+
+func TestBuildHelmTemplateArgs(t *testing.T) {
+	assert.Equal(t, []string{"./chart"}, BuildHelmTemplateArgs("./chart", &HelmTemplateFlags{}))
+
+	args := BuildHelmTemplateArgs("./chart", &HelmTemplateFlags{
+		ValueFiles:  []string{"values.yaml", "values-production.yaml"},
+		Values:      []string{"image.tag=1.2.3"},
+		Namespace:   "api",
+		KubeVersion: "1.27.0",
+	})
+	assert.Equal(t, []string{"./chart", "--values", "values.yaml", "--values", "values-production.yaml", "--set", "image.tag=1.2.3", "--namespace", "api", "--kube-version", "1.27.0"}, args)
+}
+
+func TestGetSchemaVersion(t *testing.T) {
+	assert.Equal(t, "1.27.0", getSchemaVersion("1.27.0"))
+	assert.Equal(t, "1.27.0", getSchemaVersion("v1.27.0"))
+	assert.Equal(t, "", getSchemaVersion("1.27"))
+	assert.Equal(t, "", getSchemaVersion(""))
+}
+
+func TestHelmTestCommand(t *testing.T) {
+	t.Run("should pass the helm template flags to helm", func(t *testing.T) {
+		commandRunner := &helmCommandRunnerMock{}
+		commandRunner.On("ExecuteHelmBin", []string{"./chart", "--set", "replicas=3", "--namespace", "api"}).Return([]byte(nil), errors.New("helm is not installed"))
+
+		err := runHelmTestCommand(commandRunner, []string{"./chart", "--set", "replicas=3", "--namespace", "api", "--no-record"})
+
+		assert.Equal(t, errors.New("helm is not installed"), err)
+		commandRunner.AssertNotCalled(t, "CreateTempFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should accept a kube version with a v prefix", func(t *testing.T) {
+		commandRunner := &helmCommandRunnerMock{}
+		commandRunner.On("ExecuteHelmBin", mock.Anything).Return([]byte(nil), errors.New("helm is not installed"))
+
+		err := runHelmTestCommand(commandRunner, []string{"./chart", "--kube-version", "v1.27.0", "--no-record"})
+
+		assert.Equal(t, errors.New("helm is not installed"), err)
+		commandRunner.AssertCalled(t, "ExecuteHelmBin", []string{"./chart", "--kube-version", "v1.27.0"})
+	})
+
+	t.Run("should cite the template files of a chart directory", func(t *testing.T) {
+		chartDir := t.TempDir()
+		commandRunner := &helmCommandRunnerMock{}
+		commandRunner.On("ExecuteHelmBin", []string{chartDir}).Return([]byte(renderedChart), nil)
+		commandRunner.On("CreateTempFile", mock.Anything, mock.Anything).Return("", errors.New("no space left on device"))
+
+		err := runHelmTestCommand(commandRunner, []string{chartDir, "--no-record"})
+
+		assert.Equal(t, errors.New("no space left on device"), err)
+		commandRunner.AssertCalled(t, "CreateTempFile", "datree_helm", []byte("---\n# Source: "+filepath.Join(chartDir, "templates/deployment.yaml")+"\napiVersion: apps/v1\nkind: Deployment\n"))
+	})
+
+	t.Run("should keep the sources of a chart reference", func(t *testing.T) {
+		commandRunner := &helmCommandRunnerMock{}
+		commandRunner.On("ExecuteHelmBin", []string{"bitnami/api"}).Return([]byte(renderedChart), nil)
+		commandRunner.On("CreateTempFile", mock.Anything, mock.Anything).Return("", errors.New("no space left on device"))
+
+		err := runHelmTestCommand(commandRunner, []string{"bitnami/api", "--no-record"})
+
+		assert.Equal(t, errors.New("no space left on device"), err)
+		commandRunner.AssertCalled(t, "CreateTempFile", "datree_helm", []byte(renderedChart))
+	})
+
+	t.Run("should require a chart", func(t *testing.T) {
+		err := runHelmTestCommand(&helmCommandRunnerMock{}, []string{})
+
+		assert.Equal(t, errors.New("accepts 1 arg(s), received 0"), err)
+	})
+}
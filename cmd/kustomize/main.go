@@ -8,6 +8,7 @@ import (
 
 	"github.com/datreeio/datree/cmd/test"
 	"github.com/datreeio/datree/pkg/executor"
+	"github.com/datreeio/datree/pkg/kustomization"
 	"github.com/datreeio/datree/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +30,7 @@ type KustomizeContext struct {
 
 func New(testCtx *test.TestCommandContext, kustomizeCtx *KustomizeContext) *cobra.Command {
 	testCommandFlags := test.NewTestCommandFlags()
+	buildOptions := &kustomization.BuildOptions{}
 	kustomizeTestCommand := &cobra.Command{
 		Use:   "test <args>",
 		Short: "Execute datree test for kustomize build <args>",
@@ -42,12 +44,19 @@ func New(testCtx *test.TestCommandContext, kustomizeCtx *KustomizeContext) *cobr
 
 		# Test the kustomize build from github
 		datree kustomize test https://github.com/kubernetes-sigs/kustomize.git/examples/helloWorld?ref=v1.0.6
+
+		# Test the kustomize build of a kustomization with helm charts
+		datree kustomize test ./overlays/production --enable-helm
 		`),
 		Args: func(cmd *cobra.Command, args []string) error {
 			err := utils.ValidateStdinPathArgument(args)
 			if err != nil {
 				return err
 			}
+			err = buildOptions.Validate()
+			if err != nil {
+				return err
+			}
 			return testCommandFlags.Validate()
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -62,7 +71,7 @@ func New(testCtx *test.TestCommandContext, kustomizeCtx *KustomizeContext) *cobr
 				}
 			}()
 
-			out, err := kustomizeCtx.CommandRunner.ExecuteKustomizeBin(args)
+			out, err := kustomization.BuildWithSources(kustomizeCtx.CommandRunner, args, buildOptions)
 			if err != nil {
 				return err
 			}
@@ -84,6 +93,7 @@ func New(testCtx *test.TestCommandContext, kustomizeCtx *KustomizeContext) *cobr
 		},
 	}
 	testCommandFlags.AddFlags(kustomizeTestCommand)
+	addBuildFlags(kustomizeTestCommand, buildOptions)
 
 	kustomizeCommand := &cobra.Command{
 		Use:   "kustomize",
@@ -94,3 +104,10 @@ func New(testCtx *test.TestCommandContext, kustomizeCtx *KustomizeContext) *cobr
 
 	return kustomizeCommand
 }
+
+// addBuildFlags adds the flags of kustomize build, they are passed to the in-process build and to the kustomize binary
+func addBuildFlags(cmd *cobra.Command, options *kustomization.BuildOptions) {
+	cmd.Flags().BoolVar(&options.EnableHelm, "enable-helm", false, "Enable use of the helm chart inflator generator")
+	cmd.Flags().StringVar(&options.LoadRestrictor, "load-restrictor", kustomization.LoadRestrictorRootOnly, "If set to 'LoadRestrictionsNone', local kustomizations may load files from outside their root")
+	cmd.Flags().BoolVar(&options.EnableAlphaPlugins, "enable-alpha-plugins", false, "Enable kustomize plugins")
+}
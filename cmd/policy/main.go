@@ -0,0 +1,143 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/datreeio/datree/cmd/test"
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/defaultRules"
+	"github.com/datreeio/datree/pkg/utils"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+type ShowCommandFlags struct {
+	PolicyConfig string
+	Output       string
+}
+
+// ResolvedPolicy is the policy as it's evaluated, after its extends, disable and messageOverrides are applied
+type ResolvedPolicy struct {
+	Name  string         `json:"name"`
+	Rules []ResolvedRule `json:"rules"`
+}
+
+type ResolvedRule struct {
+	Identifier       string                        `json:"identifier"`
+	Name             string                        `json:"name"`
+	Severity         string                        `json:"severity,omitempty"`
+	MessageOnFailure string                        `json:"messageOnFailure"`
+	Match            *defaultPolicies.RuleSelector `json:"match,omitempty"`
+	Exclude          *defaultPolicies.RuleSelector `json:"exclude,omitempty"`
+}
+
+func New(testCtx *test.TestCommandContext) *cobra.Command {
+	showCommandFlags := &ShowCommandFlags{}
+	showCommand := &cobra.Command{
+		Use:   "show [name]",
+		Short: "Print the rules of a policy after its inheritance is resolved",
+		Long:  "Print the rules of the given policy, or of the default policy when no name is given, with the rules of the policies it extends and without the rules it disables",
+		Example: utils.Example(`
+		# Print the default policy
+		datree policy show
+
+		# Print a policy of a local policies configuration file
+		datree policy show Production --policy-config policies.yaml -o json
+		`),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("accepts at most 1 arg(s), received %d", len(args))
+			}
+			if showCommandFlags.Output != "" && showCommandFlags.Output != "yaml" && showCommandFlags.Output != "json" {
+				return fmt.Errorf("invalid --output option - %q\nValid output values are - yaml, json", showCommandFlags.Output)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			test.SetSilentMode(cmd)
+			var err error = nil
+			defer func() {
+				if err != nil {
+					testCtx.Printer.PrintError(strings.Join([]string{"\n", err.Error(), "\n"}, ""), "error")
+				}
+			}()
+
+			var policyName string
+			if len(args) == 1 {
+				policyName = args[0]
+			}
+
+			resolvedPolicy, err := getResolvedPolicy(testCtx, policyName, showCommandFlags.PolicyConfig)
+			if err != nil {
+				return err
+			}
+
+			var output []byte
+			if showCommandFlags.Output == "json" {
+				output, err = json.MarshalIndent(resolvedPolicy, "", "  ")
+				output = append(output, '\n')
+			} else {
+				output, err = yaml.Marshal(resolvedPolicy)
+			}
+			if err != nil {
+				return err
+			}
+			testCtx.Printer.PrintMessage(string(output), "")
+			return nil
+		},
+	}
+	showCommand.Flags().StringVar(&showCommandFlags.PolicyConfig, "policy-config", "", "Path for local policies configuration file")
+	showCommand.Flags().StringVarP(&showCommandFlags.Output, "output", "o", "", "Define output type (yaml, json)")
+
+	policyCommand := &cobra.Command{
+		Use:   "policy",
+		Short: "Inspect the policies",
+	}
+
+	policyCommand.AddCommand(showCommand)
+
+	return policyCommand
+}
+
+func getResolvedPolicy(testCtx *test.TestCommandContext, policyName string, policyConfig string) (*ResolvedPolicy, error) {
+	localConfigContent, err := testCtx.LocalConfig.GetLocalConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	evaluationPrerunData, err := testCtx.CliClient.RequestEvaluationPrerunData(localConfigContent.Token, testCtx.CiContext.IsCI)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := test.GetPolicies(policyConfig, localConfigContent, evaluationPrerunData)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultRules, err := defaultRules.GetDefaultRules()
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := policy_factory.CreatePolicy(policies, policyName, evaluationPrerunData.RegistrationURL, defaultRules, evaluationPrerunData.IsAnonymous)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedPolicy := &ResolvedPolicy{Name: policy.Name, Rules: []ResolvedRule{}}
+	for _, rule := range policy.Rules {
+		resolvedPolicy.Rules = append(resolvedPolicy.Rules, ResolvedRule{
+			Identifier:       rule.RuleIdentifier,
+			Name:             rule.RuleName,
+			Severity:         rule.Severity,
+			MessageOnFailure: rule.MessageOnFailure,
+			Match:            rule.Match,
+			Exclude:          rule.Exclude,
+		})
+	}
+	return resolvedPolicy, nil
+}
@@ -0,0 +1,172 @@
+package policy
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/datreeio/datree/cmd/test"
+	"github.com/datreeio/datree/pkg/ciContext"
+	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/localConfig"
+	"github.com/datreeio/datree/pkg/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const policiesWithInheritancePath = "./test_fixtures/policiesWithInheritance.yaml"
+
+//go:embed test_fixtures/resolvedPolicy.yaml
+var resolvedPolicyYaml string
+
+//go:embed test_fixtures/resolvedPolicy.json
+var resolvedPolicyJson string
+
+type cliClientMock struct {
+	mock.Mock
+}
+
+func (c *cliClientMock) RequestEvaluationPrerunData(token string, isCi bool) (*cliClient.EvaluationPrerunDataResponse, error) {
+	args := c.Called(token, isCi)
+	return args.Get(0).(*cliClient.EvaluationPrerunDataResponse), args.Error(1)
+}
+
+func (c *cliClientMock) AddFlags(flags map[string]interface{}) {
+}
+
+type localConfigMock struct {
+	mock.Mock
+}
+
+func (lc *localConfigMock) GetLocalConfiguration() (*localConfig.LocalConfig, error) {
+	args := lc.Called()
+	return args.Get(0).(*localConfig.LocalConfig), args.Error(1)
+}
+
+type printerMock struct {
+	mock.Mock
+}
+
+func (p *printerMock) GetWarningsText(warnings []printer.Warning, quiet bool) string {
+	return ""
+}
+
+func (p *printerMock) GetSummaryTableText(summary printer.Summary) string {
+	return ""
+}
+
+func (p *printerMock) GetEvaluationSummaryText(evaluationSummary printer.EvaluationSummary, k8sVersion string) string {
+	return ""
+}
+
+func (p *printerMock) PrintError(messageText string, messageColor string) {
+	p.Called(messageText, messageColor)
+}
+
+func (p *printerMock) PrintMessage(messageText string, messageColor string) {
+	p.Called(messageText, messageColor)
+}
+
+func (p *printerMock) PrintPromptMessage(promptMessage string) {
+}
+
+func (p *printerMock) SetTheme(theme *printer.Theme) {
+}
+
+func newTestCommandContext() *test.TestCommandContext {
+	localConfigContent := &localConfigMock{}
+	localConfigContent.On("GetLocalConfiguration").Return(&localConfig.LocalConfig{Token: "134kh"}, nil)
+
+	client := &cliClientMock{}
+	client.On("RequestEvaluationPrerunData", "134kh", false).Return(&cliClient.EvaluationPrerunDataResponse{IsPolicyAsCodeMode: true}, nil)
+
+	outputPrinter := &printerMock{}
+	outputPrinter.On("PrintMessage", mock.Anything, mock.Anything)
+	outputPrinter.On("PrintError", mock.Anything, mock.Anything)
+
+	return &test.TestCommandContext{
+		CiContext:   &ciContext.CIContext{IsCI: false},
+		LocalConfig: localConfigContent,
+		CliClient:   client,
+		Printer:     outputPrinter,
+	}
+}
+
+func TestGetResolvedPolicy(t *testing.T) {
+	imageVersionRule := ResolvedRule{Identifier: "CONTAINERS_MISSING_IMAGE_VALUE_VERSION", Name: "Ensure each container image has a pinned (tag) version", Severity: "medium", MessageOnFailure: "Incorrect value for key `image` - specify an image version to avoid unpleasant \"version surprises\" in the future"}
+	memoryLimitRule := ResolvedRule{Identifier: "CONTAINERS_MISSING_MEMORY_LIMIT_KEY", Name: "Ensure each container has a configured memory limit", Severity: "high", MessageOnFailure: "Missing property object `limits.memory` - value should be within the accepted boundaries recommended by the organization"}
+	cpuLimitRule := ResolvedRule{Identifier: "CONTAINERS_MISSING_CPU_LIMIT_KEY", Name: "Ensure each container has a configured CPU limit", Severity: "high", MessageOnFailure: "Missing property object `limits.cpu` - value should be within the accepted boundaries recommended by the organization"}
+	readinessProbeRule := ResolvedRule{Identifier: "CONTAINERS_MISSING_READINESSPROBE_KEY", Name: "Ensure each container has a configured readiness probe", Severity: "high", MessageOnFailure: "Missing property object `readinessProbe` - add a properly configured readinessProbe to notify kubelet your Pods are ready for traffic", Exclude: &defaultPolicies.RuleSelector{Namespaces: []string{"kube-*"}}}
+
+	productionImageVersionRule := imageVersionRule
+	productionImageVersionRule.MessageOnFailure = "Production images must be pinned to a version"
+
+	tests := []struct {
+		name                   string
+		policyName             string
+		expectedResolvedPolicy *ResolvedPolicy
+	}{
+		{
+			name:       "default policy with the rules it extends, without the rules it disables and with its message overrides",
+			policyName: "",
+			expectedResolvedPolicy: &ResolvedPolicy{
+				Name:  "Production",
+				Rules: []ResolvedRule{productionImageVersionRule, memoryLimitRule, readinessProbeRule},
+			},
+		},
+		{
+			name:       "extended policy keeps its own rules and messages",
+			policyName: "Base",
+			expectedResolvedPolicy: &ResolvedPolicy{
+				Name:  "Base",
+				Rules: []ResolvedRule{imageVersionRule, memoryLimitRule, cpuLimitRule},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolvedPolicy, err := getResolvedPolicy(newTestCommandContext(), tt.policyName, policiesWithInheritancePath)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResolvedPolicy, resolvedPolicy)
+		})
+	}
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := getResolvedPolicy(newTestCommandContext(), "Staging", policiesWithInheritancePath)
+
+		assert.EqualError(t, err, "policy Staging doesn't exist")
+	})
+}
+
+func TestShowCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		arguments      []string
+		expectedOutput string
+	}{
+		{name: "default output", arguments: []string{"--policy-config", policiesWithInheritancePath}, expectedOutput: resolvedPolicyYaml},
+		{name: "yaml output", arguments: []string{"--policy-config", policiesWithInheritancePath, "--output", "yaml"}, expectedOutput: resolvedPolicyYaml},
+		{name: "json output", arguments: []string{"Production", "--policy-config", policiesWithInheritancePath, "-o", "json"}, expectedOutput: resolvedPolicyJson},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCtx := newTestCommandContext()
+			showCommand := New(testCtx).Commands()[0]
+			err := showCommand.ParseFlags(tt.arguments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := showCommand.Flags().Args()
+			err = showCommand.Args(showCommand, args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = showCommand.RunE(showCommand, args)
+
+			assert.NoError(t, err)
+			testCtx.Printer.(*printerMock).AssertCalled(t, "PrintMessage", tt.expectedOutput, "")
+		})
+	}
+}
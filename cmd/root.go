@@ -12,7 +12,10 @@ import (
 	"github.com/datreeio/datree/cmd/completion"
 	"github.com/datreeio/datree/cmd/config"
 	"github.com/datreeio/datree/cmd/docs"
+	"github.com/datreeio/datree/cmd/exceptions"
+	"github.com/datreeio/datree/cmd/helm"
 	"github.com/datreeio/datree/cmd/kustomize"
+	"github.com/datreeio/datree/cmd/policy"
 	"github.com/datreeio/datree/cmd/publish"
 	schemaValidator "github.com/datreeio/datree/cmd/schema-validator"
 	"github.com/datreeio/datree/cmd/test"
@@ -25,6 +28,7 @@ import (
 	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
 	"github.com/datreeio/datree/pkg/localConfig"
 	"github.com/datreeio/datree/pkg/printer"
+	"github.com/datreeio/datree/pkg/renderer"
 	"github.com/spf13/cobra"
 )
 
@@ -53,6 +57,7 @@ func NewRootCommand(app *App) *cobra.Command {
 		CiContext:          app.Context.CiContext,
 		OpenBrowserContext: utils.OpenBrowserContext{},
 		StartTime:          startTime,
+		Renderer:           renderer.New(app.Context.CommandRunner),
 	}))
 
 	rootCmd.AddCommand(kustomize.New(&test.TestCommandContext{
@@ -69,6 +74,43 @@ func NewRootCommand(app *App) *cobra.Command {
 		StartTime:      startTime,
 	}, &kustomize.KustomizeContext{CommandRunner: app.Context.CommandRunner}))
 
+	rootCmd.AddCommand(helm.New(&test.TestCommandContext{
+		CliVersion:     CliVersion,
+		Evaluator:      app.Context.Evaluator,
+		LocalConfig:    app.Context.LocalConfig,
+		Messager:       app.Context.Messager,
+		Printer:        app.Context.Printer,
+		Reader:         app.Context.Reader,
+		K8sValidator:   app.Context.K8sValidator,
+		CliClient:      app.Context.CliClient,
+		FilesExtractor: app.Context.FilesExtractor,
+		CiContext:      app.Context.CiContext,
+		StartTime:      startTime,
+	}, &helm.HelmContext{CommandRunner: app.Context.CommandRunner}))
+
+	rootCmd.AddCommand(exceptions.New(&test.TestCommandContext{
+		CliVersion:     CliVersion,
+		Evaluator:      app.Context.Evaluator,
+		LocalConfig:    app.Context.LocalConfig,
+		Messager:       app.Context.Messager,
+		Printer:        app.Context.Printer,
+		Reader:         app.Context.Reader,
+		K8sValidator:   app.Context.K8sValidator,
+		CliClient:      app.Context.CliClient,
+		FilesExtractor: app.Context.FilesExtractor,
+		CiContext:      app.Context.CiContext,
+		StartTime:      startTime,
+	}))
+
+	rootCmd.AddCommand(policy.New(&test.TestCommandContext{
+		CliVersion:  CliVersion,
+		LocalConfig: app.Context.LocalConfig,
+		Printer:     app.Context.Printer,
+		CliClient:   app.Context.CliClient,
+		CiContext:   app.Context.CiContext,
+		StartTime:   startTime,
+	}))
+
 	rootCmd.AddCommand(version.New(&version.VersionCommandContext{
 		CliVersion: CliVersion,
 		Messager:   app.Context.Messager,
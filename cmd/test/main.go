@@ -16,11 +16,13 @@ import (
 	"github.com/datreeio/datree/bl/messager"
 	policy_factory "github.com/datreeio/datree/bl/policy"
 	"github.com/datreeio/datree/bl/validation"
+	"github.com/datreeio/datree/pkg/baseline"
 	"github.com/datreeio/datree/pkg/ciContext"
 	"github.com/datreeio/datree/pkg/cliClient"
 	"github.com/datreeio/datree/pkg/defaultPolicies"
 	"github.com/datreeio/datree/pkg/defaultRules"
 	"github.com/datreeio/datree/pkg/evaluation"
+	"github.com/datreeio/datree/pkg/exceptions"
 	"github.com/datreeio/datree/pkg/policy"
 	"github.com/pkg/errors"
 	"k8s.io/utils/strings/slices"
@@ -28,6 +30,8 @@ import (
 	"github.com/datreeio/datree/pkg/extractor"
 	"github.com/datreeio/datree/pkg/localConfig"
 	"github.com/datreeio/datree/pkg/printer"
+	"github.com/datreeio/datree/pkg/regoPolicies"
+	"github.com/datreeio/datree/pkg/renderer"
 	"github.com/datreeio/datree/pkg/utils"
 
 	"github.com/eiannone/keyboard"
@@ -65,6 +69,13 @@ type TestCommandFlags struct {
 	SaveRendered         bool
 	PermissiveSchema     bool
 	Quiet                bool
+	RegoDir              string
+	FailOn               string
+	Baseline             string
+	WriteBaseline        string
+	Exceptions           string
+	GatingPolicy         string
+	Render               bool
 }
 
 // TestCommandFlags constructor
@@ -83,9 +94,25 @@ func NewTestCommandFlags() *TestCommandFlags {
 		SaveRendered:         false,
 		PermissiveSchema:     false,
 		Quiet:                false,
+		RegoDir:              "",
+		FailOn:               "",
+		Baseline:             "",
+		WriteBaseline:        "",
+		Exceptions:           "",
+		GatingPolicy:         "",
+		Render:               false,
 	}
 }
 
+// PolicyNames returns the names of the policies of the --policy flag, an empty name stands for the default policy
+func (flags *TestCommandFlags) PolicyNames() []string {
+	var policyNames []string
+	for _, policyName := range strings.Split(flags.PolicyName, ",") {
+		policyNames = append(policyNames, strings.TrimSpace(policyName))
+	}
+	return policyNames
+}
+
 func validateSkipValidationFlag(flags *TestCommandFlags) error {
 	supportedSkipValidation := []string{"schema"}
 
@@ -120,6 +147,25 @@ func (flags *TestCommandFlags) Validate() error {
 		return fmt.Errorf("invalid --exclude flag: " + err.Error())
 	}
 
+	policyNames := flags.PolicyNames()
+	for index, policyName := range policyNames {
+		if len(policyNames) > 1 && policyName == "" {
+			return fmt.Errorf("invalid --policy option - %q\nPolicy names must not be empty", flags.PolicyName)
+		}
+		if slices.Contains(policyNames[:index], policyName) {
+			return fmt.Errorf("invalid --policy option - %q\nPolicy %s is set more than once", flags.PolicyName, policyName)
+		}
+	}
+
+	if flags.GatingPolicy != "" && !slices.Contains(policyNames, flags.GatingPolicy) {
+		return fmt.Errorf("invalid --gating-policy option - %q\nThe gating policy must be one of the policies of the --policy flag", flags.GatingPolicy)
+	}
+
+	if flags.FailOn != "" && !policy_factory.IsValidSeverity(flags.FailOn) {
+		return fmt.Errorf("invalid --fail-on option - %q\n"+
+			"Valid fail-on values are - "+strings.Join(policy_factory.Severities, ", "), flags.FailOn)
+	}
+
 	return nil
 
 }
@@ -134,6 +180,11 @@ type EvaluationPrinter interface {
 	SetTheme(theme *printer.Theme)
 }
 
+// Renderer renders the helm charts and kustomizations of the render mode
+type Renderer interface {
+	Render(source renderer.Source) ([]byte, error)
+}
+
 type Reader interface {
 	FilterFiles(paths []string, excludePattern string) ([]string, error)
 }
@@ -150,15 +201,19 @@ type CliClient interface {
 }
 
 type TestCommandData struct {
-	Output                string
-	SaveResults           string
-	K8sVersion            string
-	ExcludePattern        string
-	IgnoreMissingSchemas  bool
-	OnlyK8sFiles          bool
-	Verbose               bool
-	NoRecord              bool
-	Policy                policy_factory.Policy
+	Output               string
+	SaveResults          string
+	K8sVersion           string
+	ExcludePattern       string
+	IgnoreMissingSchemas bool
+	OnlyK8sFiles         bool
+	Verbose              bool
+	NoRecord             bool
+	// Policy is the gating policy, its results drive the exit code of the run
+	Policy policy_factory.Policy
+	// Policies are evaluated against the same configurations, they include the gating policy at GatingPolicyIndex
+	Policies              []policy_factory.Policy
+	GatingPolicyIndex     int
 	SchemaLocations       []string
 	Token                 string
 	RegistrationURL       string
@@ -169,6 +224,16 @@ type TestCommandData struct {
 	PermissiveSchema      bool
 	Quiet                 bool
 	IsOffline             bool
+	// FailOn is the least severe severity of the failed rules that fail the run, all failed rules fail the run when it's empty
+	FailOn string
+	// Baseline hides the known violations, only new violations are reported
+	Baseline      *baseline.Baseline
+	WriteBaseline string
+	// Exceptions skip the failures they match, ExceptionsPath is the file they were loaded from
+	Exceptions     *exceptions.Exceptions
+	ExceptionsPath string
+	// Render replaces the files of helm charts and kustomizations with their rendered resources
+	Render bool
 }
 
 type TestCommandContext struct {
@@ -184,6 +249,7 @@ type TestCommandContext struct {
 	FilesExtractor     files.FilesExtractorInterface
 	StartTime          time.Time
 	OpenBrowserContext utils.OpenBrowserContext
+	Renderer           Renderer
 }
 
 func LoadVersionMessages(ctx *TestCommandContext, args []string, cmd *cobra.Command) error {
@@ -218,6 +284,9 @@ func New(ctx *TestCommandContext) *cobra.Command {
 
 		# Test the configuration by sending manifests through stdin
 		cat kube-prod/deployment.yaml | datree test -
+
+		# Test the rendered resources of the helm charts and kustomizations, and the other files as is
+		datree test deploy/**/*.yaml --render
 		`),
 		Args: func(cmd *cobra.Command, args []string) error {
 			err := utils.ValidateStdinPathArgument(args)
@@ -246,6 +315,8 @@ func New(ctx *TestCommandContext) *cobra.Command {
 		},
 	}
 	testCommandFlags.AddFlags(testCommand)
+	// the commands that render their input themselves don't support the render mode
+	testCommand.Flags().BoolVar(&testCommandFlags.Render, "render", false, "Render the helm charts and kustomizations that contain the files with helm template and kustomize build, and test their rendered resources instead of their files")
 	return testCommand
 }
 
@@ -271,10 +342,12 @@ func (flags *TestCommandFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&flags.Output, "output", "o", defaultOutputValue, "Define output format ("+evaluation.OutputFormats()+")")
 
 	cmd.Flags().StringVarP(&flags.K8sVersion, "schema-version", "s", "", "Set kubernetes version to validate against. Defaults to 1.24.0")
-	cmd.Flags().StringVarP(&flags.PolicyName, "policy", "p", "", "Policy name to run against")
+	cmd.Flags().StringVarP(&flags.PolicyName, "policy", "p", "", "Policy name to run against, or comma separated policies names to run against in one run")
+	cmd.Flags().StringVar(&flags.GatingPolicy, "gating-policy", "", "The policy whose results drive the exit code when several policies are set. Defaults to the first policy")
 	cmd.Flags().StringVarP(&flags.ExcludePattern, "exclude", "", "", "Exclude paths pattern (regex)")
 
 	cmd.Flags().StringVar(&flags.PolicyConfig, "policy-config", "", "Path for local policies configuration file")
+	cmd.Flags().StringVar(&flags.RegoDir, "rego-dir", "", "Path for a directory of conftest style rego policies (deny, violation and warn rules) to run next to the policy")
 	cmd.Flags().BoolVar(&flags.OnlyK8sFiles, "only-k8s-files", false, "Evaluate only valid yaml files with the properties 'apiVersion' and 'kind'. Ignore everything else")
 	cmd.Flags().BoolVar(&flags.Verbose, "verbose", false, "Display 'How to Fix' link")
 	cmd.Flags().BoolVar(&flags.NoRecord, "no-record", false, "Don’t send policy checks metadata to the backend")
@@ -288,6 +361,10 @@ func (flags *TestCommandFlags) AddFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&flags.SaveRendered, "save-rendered", "", false, "Don't delete rendered files after the policy check (e.g. helm, kustomize)")
 	cmd.Flags().BoolVarP(&flags.PermissiveSchema, "permissive-schema", "", false, "Perform non-strict schema validation (i.e. allow additional properties)")
 	cmd.Flags().BoolVarP(&flags.Quiet, "quiet", "", false, "Don't print skipped rules messages")
+	cmd.Flags().StringVar(&flags.Baseline, "baseline", "", "Path for a baseline file, violations that are in the baseline are hidden and only new violations are reported")
+	cmd.Flags().StringVar(&flags.WriteBaseline, "write-baseline", "", "Path to write a baseline file of the current violations to")
+	cmd.Flags().StringVar(&flags.Exceptions, "exceptions", "", "Path for an exceptions file, the failures of the rules and resources that an exception matches are skipped")
+	cmd.Flags().StringVar(&flags.FailOn, "fail-on", "", "Fail only on failed rules of this severity or higher ("+strings.Join(policy_factory.Severities, ", ")+"). Rules without a severity are "+policy_factory.DefaultSeverity)
 }
 
 const (
@@ -308,39 +385,37 @@ func GenerateTestCommandData(testCommandFlags *TestCommandFlags, localConfigCont
 		k8sVersion = "1.24.0"
 	}
 
-	var policies *defaultPolicies.EvaluationPrerunPolicies
-	var err error
-
-	var policyConfig string
-	if testCommandFlags.PolicyConfig != "" {
-		policyConfig = testCommandFlags.PolicyConfig
-	} else if policyConfigEnv, ok := os.LookupEnv(DatreePolicyConfig); ok {
-		policyConfig = policyConfigEnv
-	} else if localConfigContent.PolicyConfig != "" {
-		policyConfig = localConfigContent.PolicyConfig
+	policies, err := GetPolicies(testCommandFlags.PolicyConfig, localConfigContent, evaluationPrerunDataResp)
+	if err != nil {
+		return nil, err
 	}
 
-	if policyConfig != "" {
-		if localConfigContent.Offline != "local" && !evaluationPrerunDataResp.IsPolicyAsCodeMode {
-			return nil, fmt.Errorf("to use custom policy-config you must first enable policy-as-code mode: https://hub.datree.io/policy-as-code")
-		}
+	defaultRules, err := defaultRules.GetDefaultRules()
+	if err != nil {
+		return nil, err
+	}
 
-		policies, err = policy.GetPoliciesFileFromPath(policyConfig)
+	var regoRules []policy_factory.RuleWithSchema
+	if testCommandFlags.RegoDir != "" {
+		regoRules, err = regoPolicies.LoadRules(testCommandFlags.RegoDir)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		policies = evaluationPrerunDataResp.PoliciesJson
 	}
 
-	defaultRules, err := defaultRules.GetDefaultRules()
-	if err != nil {
-		return nil, err
-	}
+	var policiesToEvaluate []policy_factory.Policy
+	gatingPolicyIndex := 0
+	for index, policyName := range testCommandFlags.PolicyNames() {
+		policy, err := policy_factory.CreatePolicy(policies, policyName, evaluationPrerunDataResp.RegistrationURL, defaultRules, evaluationPrerunDataResp.IsAnonymous)
+		if err != nil {
+			return nil, err
+		}
+		policy.Rules = append(policy.Rules, regoRules...)
 
-	policy, err := policy_factory.CreatePolicy(policies, testCommandFlags.PolicyName, evaluationPrerunDataResp.RegistrationURL, defaultRules, evaluationPrerunDataResp.IsAnonymous)
-	if err != nil {
-		return nil, err
+		if policyName == testCommandFlags.GatingPolicy {
+			gatingPolicyIndex = index
+		}
+		policiesToEvaluate = append(policiesToEvaluate, policy)
 	}
 
 	var schemaLocations []string
@@ -352,6 +427,29 @@ func GenerateTestCommandData(testCommandFlags *TestCommandFlags, localConfigCont
 		schemaLocations = localConfigContent.SchemaLocations
 	}
 
+	var knownViolationsBaseline *baseline.Baseline
+	if testCommandFlags.Baseline != "" {
+		knownViolationsBaseline, err = baseline.Load(testCommandFlags.Baseline)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var exceptionsPath string
+	if testCommandFlags.Exceptions != "" {
+		exceptionsPath = testCommandFlags.Exceptions
+	} else if localConfigContent.Exceptions != "" {
+		exceptionsPath = localConfigContent.Exceptions
+	}
+
+	var policyExceptions *exceptions.Exceptions
+	if exceptionsPath != "" {
+		policyExceptions, err = exceptions.Load(exceptionsPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	testCommandOptions := &TestCommandData{Output: testCommandFlags.Output,
 		SaveResults:           testCommandFlags.SaveResults,
 		K8sVersion:            k8sVersion,
@@ -360,7 +458,9 @@ func GenerateTestCommandData(testCommandFlags *TestCommandFlags, localConfigCont
 		OnlyK8sFiles:          testCommandFlags.OnlyK8sFiles,
 		Verbose:               testCommandFlags.Verbose,
 		NoRecord:              testCommandFlags.NoRecord,
-		Policy:                policy,
+		Policy:                policiesToEvaluate[gatingPolicyIndex],
+		Policies:              policiesToEvaluate,
+		GatingPolicyIndex:     gatingPolicyIndex,
 		SchemaLocations:       schemaLocations,
 		Token:                 localConfigContent.Token,
 		ClientId:              localConfigContent.ClientId,
@@ -371,11 +471,38 @@ func GenerateTestCommandData(testCommandFlags *TestCommandFlags, localConfigCont
 		PermissiveSchema:      testCommandFlags.PermissiveSchema,
 		Quiet:                 testCommandFlags.Quiet,
 		IsOffline:             localConfigContent.Offline == "local",
+		FailOn:                testCommandFlags.FailOn,
+		Baseline:              knownViolationsBaseline,
+		WriteBaseline:         testCommandFlags.WriteBaseline,
+		Exceptions:            policyExceptions,
+		ExceptionsPath:        exceptionsPath,
+		Render:                testCommandFlags.Render,
 	}
 
 	return testCommandOptions, nil
 }
 
+// GetPolicies returns the policies of the policy config file, set by flag, env or config in that order, or the policies of the account
+func GetPolicies(policyConfigFlag string, localConfigContent *localConfig.LocalConfig, evaluationPrerunDataResp *cliClient.EvaluationPrerunDataResponse) (*defaultPolicies.EvaluationPrerunPolicies, error) {
+	var policyConfig string
+	if policyConfigFlag != "" {
+		policyConfig = policyConfigFlag
+	} else if policyConfigEnv, ok := os.LookupEnv(DatreePolicyConfig); ok {
+		policyConfig = policyConfigEnv
+	} else if localConfigContent.PolicyConfig != "" {
+		policyConfig = localConfigContent.PolicyConfig
+	}
+
+	if policyConfig == "" {
+		return evaluationPrerunDataResp.PoliciesJson, nil
+	}
+
+	if localConfigContent.Offline != "local" && !evaluationPrerunDataResp.IsPolicyAsCodeMode {
+		return nil, fmt.Errorf("to use custom policy-config you must first enable policy-as-code mode: https://hub.datree.io/policy-as-code")
+	}
+	return policy.GetPoliciesFileFromPath(policyConfig)
+}
+
 func validateK8sVersionFormatIfProvided(k8sVersion string) error {
 	if k8sVersion == "" {
 		return nil
@@ -411,6 +538,40 @@ func TestWrapper(ctx *TestCommandContext, args []string, testCommandFlags *TestC
 	return test(ctx, args, testCommandOptions)
 }
 
+// EvaluateWrapper runs the policy check of the paths without printing or recording its results, for commands that report on the run
+func EvaluateWrapper(ctx *TestCommandContext, paths []string, testCommandFlags *TestCommandFlags) (*TestCommandData, EvaluationResultData, error) {
+	localConfigContent, err := ctx.LocalConfig.GetLocalConfiguration()
+	if err != nil {
+		return nil, EvaluationResultData{}, err
+	}
+
+	ctx.CliClient.AddFlags(testCommandFlags.ToMapping())
+	evaluationPrerunData, err := ctx.CliClient.RequestEvaluationPrerunData(localConfigContent.Token, ctx.CiContext.IsCI)
+	if err != nil {
+		return nil, EvaluationResultData{}, err
+	}
+
+	testCommandData, err := GenerateTestCommandData(testCommandFlags, localConfigContent, evaluationPrerunData)
+	if err != nil {
+		return nil, EvaluationResultData{}, err
+	}
+	testCommandData.NoRecord = true
+
+	filesPaths, err := ctx.Reader.FilterFiles(paths, testCommandData.ExcludePattern)
+	if err != nil {
+		return nil, EvaluationResultData{}, err
+	}
+	if len(filesPaths) == 0 {
+		return nil, EvaluationResultData{}, fmt.Errorf("no files detected")
+	}
+
+	evaluationResultData, err := evaluate(ctx, filesPaths, testCommandData)
+	if err != nil {
+		return nil, EvaluationResultData{}, err
+	}
+	return testCommandData, evaluationResultData, nil
+}
+
 func test(ctx *TestCommandContext, paths []string, testCommandData *TestCommandData) error {
 	if paths[0] == "-" {
 		tempFile, err := os.CreateTemp("", "datree_temp_*.yaml")
@@ -432,6 +593,18 @@ func test(ctx *TestCommandContext, paths []string, testCommandData *TestCommandD
 	if err != nil {
 		return err
 	}
+
+	if testCommandData.Render {
+		var renderedFilesPaths []string
+		filesPaths, renderedFilesPaths, err = renderSources(ctx.Renderer, filesPaths)
+		if !testCommandData.SaveRendered {
+			defer removeFiles(renderedFilesPaths)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	filesCount := len(filesPaths)
 	if filesCount == 0 {
 		noFilesErr := fmt.Errorf("no files detected")
@@ -459,6 +632,12 @@ func test(ctx *TestCommandContext, paths []string, testCommandData *TestCommandD
 		filesCount -= validationManager.IgnoredFilesCount()
 	}
 
+	// a rendered file is evaluated as the files of the sources it was rendered from
+	policyCheckFilesCount := filesCount
+	if results.EvaluationResults != nil {
+		policyCheckFilesCount += results.EvaluationResults.Summary.FilesCount - len(validationManager.ValidOrSkippedK8sFilesConfigurations())
+	}
+
 	passedYamlValidationCount := filesCount - validationManager.InvalidYamlFilesCount()
 
 	evaluationSummary := printer.EvaluationSummary{
@@ -468,6 +647,7 @@ func test(ctx *TestCommandContext, paths []string, testCommandData *TestCommandD
 		K8sValidation:             validationManager.GetK8sValidationSummaryStr(filesCount),
 		ConfigsCount:              validationManager.ValidK8sConfigurationsCount(),
 		PassedPolicyCheckCount:    passedPolicyCheckCount,
+		PolicyCheckFilesCount:     policyCheckFilesCount,
 	}
 
 	evaluationData := &evaluation.PrintResultsData{
@@ -486,6 +666,7 @@ func test(ctx *TestCommandContext, paths []string, testCommandData *TestCommandD
 		CliVersion:            ctx.CliVersion,
 		IsCI:                  ctx.CiContext.IsCI,
 		Quiet:                 testCommandData.Quiet,
+		PoliciesResults:       evaluationResultData.PoliciesResults,
 	}
 	err = evaluation.PrintResults(evaluationData)
 
@@ -522,23 +703,73 @@ func test(ctx *TestCommandContext, paths []string, testCommandData *TestCommandD
 		ctx.Printer.PrintError("\n[INFO] You're running Datree in offline mode", "cyan")
 	}
 
+	if testCommandData.WriteBaseline != "" {
+		ctx.Printer.PrintError("\n[INFO] The violations were written to the baseline file "+testCommandData.WriteBaseline, "cyan")
+	}
+
 	if err != nil {
 		return err
 	}
 
-	if wereViolationsFound(validationManager, &results) {
+	// expired exceptions fail the run until they are renewed or removed
+	if testCommandData.Exceptions != nil {
+		if expiredExceptions := testCommandData.Exceptions.Expired(); len(expiredExceptions) > 0 {
+			ctx.Printer.PrintError(getExpiredExceptionsText(expiredExceptions, testCommandData.ExceptionsPath), "error")
+			return ViolationsFoundError
+		}
+	}
+
+	if wereViolationsFound(validationManager, &results, testCommandData.FailOn) {
 		return ViolationsFoundError
 	}
 
 	return nil
 }
 
+// renderSources renders the helm charts and kustomizations that own the files to temp files, which replace the files they own.
+// It returns the files to test and the rendered files
+func renderSources(sourcesRenderer Renderer, filesPaths []string) ([]string, []string, error) {
+	sources, unownedFilesPaths, err := renderer.FindSources(filesPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var renderedFilesPaths []string
+	for _, source := range sources {
+		out, err := sourcesRenderer.Render(source)
+		if err != nil {
+			return nil, renderedFilesPaths, err
+		}
+
+		tempFile, err := os.CreateTemp("", "datree_render_*.yaml")
+		if err != nil {
+			return nil, renderedFilesPaths, err
+		}
+		renderedFilesPaths = append(renderedFilesPaths, tempFile.Name())
+
+		_, err = tempFile.Write(out)
+		tempFile.Close()
+		if err != nil {
+			return nil, renderedFilesPaths, err
+		}
+	}
+	return append(unownedFilesPaths, renderedFilesPaths...), renderedFilesPaths, nil
+}
+
+func removeFiles(filesPaths []string) {
+	for _, filePath := range filesPaths {
+		os.Remove(filePath)
+	}
+}
+
 type EvaluationResultData struct {
 	ValidationManager   *ValidationManager
 	RulesCount          int
 	FormattedResults    evaluation.FormattedResults
 	AdditionalJUnitData evaluation.AdditionalJUnitData
 	PromptMessage       string
+	// PoliciesResults is set when several policies are evaluated, the other fields are of the gating policy
+	PoliciesResults []evaluation.PolicyResultsData
 }
 
 func evaluate(ctx *TestCommandContext, filesPaths []string, testCommandData *TestCommandData) (EvaluationResultData, error) {
@@ -590,17 +821,41 @@ func evaluate(ctx *TestCommandContext, filesPaths []string, testCommandData *Tes
 
 	wg.Wait()
 
-	policyName := testCommandData.Policy.Name
+	policies := testCommandData.Policies
+	if len(policies) == 0 {
+		policies = []policy_factory.Policy{testCommandData.Policy}
+	}
 
-	policyCheckData := evaluation.PolicyCheckData{
-		FilesConfigurations: validationManager.ValidOrSkippedK8sFilesConfigurations(),
-		IsInteractiveMode:   isInteractiveMode,
-		PolicyName:          policyName,
-		Policy:              testCommandData.Policy,
-		Verbose:             testCommandData.Verbose,
+	// every policy is evaluated against the configurations that were extracted and validated once
+	var evaluationResultData EvaluationResultData
+	var policiesResults []evaluation.PolicyResultsData
+	for index, policy := range policies {
+		isGating := index == testCommandData.GatingPolicyIndex
+		policyEvaluationResultData, err := evaluatePolicy(ctx, policy, isGating, validationManager, testCommandData, isInteractiveMode)
+		if err != nil {
+			return emptyEvaluationResultData(), err
+		}
+
+		if isGating {
+			evaluationResultData = policyEvaluationResultData
+		}
+		policiesResults = append(policiesResults, evaluation.PolicyResultsData{
+			PolicyName:          policy.Name,
+			IsGating:            isGating,
+			RulesCount:          policyEvaluationResultData.RulesCount,
+			Results:             policyEvaluationResultData.FormattedResults,
+			AdditionalJUnitData: policyEvaluationResultData.AdditionalJUnitData,
+		})
 	}
 
-	emptyEvaluationResultData := EvaluationResultData{
+	if len(policies) > 1 {
+		evaluationResultData.PoliciesResults = policiesResults
+	}
+	return evaluationResultData, nil
+}
+
+func emptyEvaluationResultData() EvaluationResultData {
+	return EvaluationResultData{
 		ValidationManager: nil,
 		RulesCount:        0,
 		FormattedResults:  evaluation.FormattedResults{},
@@ -610,10 +865,34 @@ func evaluate(ctx *TestCommandContext, filesPaths []string, testCommandData *Tes
 		},
 		PromptMessage: "",
 	}
+}
+
+// evaluatePolicy runs the policy check of a policy. The baseline is written from the results of the gating policy,
+// and only its results are sent so a run is recorded once no matter how many policies it evaluates
+func evaluatePolicy(ctx *TestCommandContext, policy policy_factory.Policy, isGating bool, validationManager *ValidationManager, testCommandData *TestCommandData, isInteractiveMode bool) (EvaluationResultData, error) {
+	policyName := policy.Name
+
+	policyCheckData := evaluation.PolicyCheckData{
+		FilesConfigurations: validationManager.ValidOrSkippedK8sFilesConfigurations(),
+		IsInteractiveMode:   isInteractiveMode,
+		PolicyName:          policyName,
+		Policy:              policy,
+		Verbose:             testCommandData.Verbose,
+		Baseline:            testCommandData.Baseline,
+		WriteBaseline:       isGating && testCommandData.WriteBaseline != "",
+		Exceptions:          testCommandData.Exceptions,
+	}
 
 	policyCheckResultData, err := ctx.Evaluator.Evaluate(policyCheckData)
 	if err != nil {
-		return emptyEvaluationResultData, err
+		return emptyEvaluationResultData(), err
+	}
+
+	if isGating && testCommandData.WriteBaseline != "" && policyCheckResultData.NewBaseline != nil {
+		err = policyCheckResultData.NewBaseline.Write(testCommandData.WriteBaseline)
+		if err != nil {
+			return emptyEvaluationResultData(), err
+		}
 	}
 
 	additionalJUnitData := evaluation.AdditionalJUnitData{
@@ -621,7 +900,7 @@ func evaluate(ctx *TestCommandContext, filesPaths []string, testCommandData *Tes
 		AllFilesThatRanPolicyCheck: utils.MapSlice[cliClient.FileData, string](policyCheckResultData.FilesData, func(fileData cliClient.FileData) string { return fileData.FilePath }),
 	}
 
-	if testCommandData.NoRecord {
+	if testCommandData.NoRecord || !isGating {
 		return EvaluationResultData{
 			ValidationManager:   validationManager,
 			RulesCount:          policyCheckResultData.RulesCount,
@@ -666,7 +945,7 @@ func evaluate(ctx *TestCommandContext, filesPaths []string, testCommandData *Tes
 	sendEvaluationResultsResponse, err := ctx.Evaluator.SendEvaluationResult(evaluationRequestData)
 
 	if err != nil {
-		return emptyEvaluationResultData, err
+		return emptyEvaluationResultData(), err
 	}
 
 	evaluationResultData := EvaluationResultData{
@@ -680,18 +959,38 @@ func evaluate(ctx *TestCommandContext, filesPaths []string, testCommandData *Tes
 	return evaluationResultData, nil
 }
 
-func wereViolationsFound(validationManager *ValidationManager, results *evaluation.FormattedResults) bool {
+func wereViolationsFound(validationManager *ValidationManager, results *evaluation.FormattedResults, failOn string) bool {
 	if validationManager.InvalidYamlFilesCount() > 0 {
 		return true
 	} else if validationManager.InvalidK8sFilesCount() > 0 {
 		return true
-	} else if results.EvaluationResults != nil && results.EvaluationResults.Summary.TotalFailedRules > 0 {
-		return true
+	} else if results.EvaluationResults != nil && failOn == "" {
+		return results.EvaluationResults.Summary.TotalFailedRules > 0
+	} else if results.EvaluationResults != nil {
+		return countFailedRulesOfSeverityAtLeast(results.EvaluationResults.Summary.TotalFailedRulesBySeverity, failOn) > 0
 	} else {
 		return false
 	}
 }
 
+func getExpiredExceptionsText(expiredExceptions []*exceptions.Exception, exceptionsPath string) string {
+	text := fmt.Sprintf("\n[X] %d expired exceptions in %s, renew or remove them:\n", len(expiredExceptions), exceptionsPath)
+	for _, exception := range expiredExceptions {
+		text += "- " + exception.Description() + "\n"
+	}
+	return text
+}
+
+func countFailedRulesOfSeverityAtLeast(totalFailedRulesBySeverity map[string]int, failOn string) int {
+	count := 0
+	for severity, failedRulesCount := range totalFailedRulesBySeverity {
+		if policy_factory.IsSeverityAtLeast(severity, failOn) {
+			count += failedRulesCount
+		}
+	}
+	return count
+}
+
 func saveDefaultRulesAsFile(ctx *TestCommandContext, preRunDefaultRulesYaml string) {
 	if preRunDefaultRulesYaml == "" {
 		return
@@ -2,6 +2,7 @@ package test
 
 import (
 	"encoding/json"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/datreeio/datree/pkg/defaultRules"
 
 	"github.com/datreeio/datree/pkg/evaluation"
+	"github.com/datreeio/datree/pkg/exceptions"
 
 	"github.com/datreeio/datree/bl/files"
 	"github.com/datreeio/datree/bl/messager"
@@ -20,6 +22,7 @@ import (
 	"github.com/datreeio/datree/pkg/cliClient"
 	"github.com/datreeio/datree/pkg/extractor"
 	"github.com/datreeio/datree/pkg/printer"
+	"github.com/datreeio/datree/pkg/renderer"
 	"github.com/pkg/errors"
 
 	"github.com/datreeio/datree/pkg/localConfig"
@@ -749,6 +752,75 @@ func TestTestCommandFlagsValidation(t *testing.T) {
 	test_testCommand_version_flags_validation(t, ctx)
 	test_testCommand_no_record_flag(t, ctx)
 	test_testCommand_save_results_flag(t, ctx)
+	test_testCommand_fail_on_flag_validation(t, ctx)
+	test_testCommand_policy_flag_validation(t, ctx)
+}
+
+func TestWereViolationsFound(t *testing.T) {
+	results := &evaluation.FormattedResults{
+		EvaluationResults: &evaluation.EvaluationResults{
+			Summary: evaluation.EvaluationResultsSummery{
+				TotalFailedRules:           3,
+				TotalFailedRulesBySeverity: map[string]int{"medium": 2, "low": 1},
+			},
+		},
+	}
+
+	assert.True(t, wereViolationsFound(NewValidationManager(), results, ""))
+	assert.True(t, wereViolationsFound(NewValidationManager(), results, "low"))
+	assert.True(t, wereViolationsFound(NewValidationManager(), results, "medium"))
+	assert.False(t, wereViolationsFound(NewValidationManager(), results, "high"))
+	assert.False(t, wereViolationsFound(NewValidationManager(), &evaluation.FormattedResults{}, "info"))
+}
+
+func TestGetExpiredExceptionsText(t *testing.T) {
+	expiredExceptions := []*exceptions.Exception{
+		{Rule: "CONTAINERS_MISSING_LIVENESSPROBE_KEY", File: "legacy/**", Owner: "web-team", Ticket: "WEB-42", Reason: "no health endpoint", Expires: "2026-01-31"},
+	}
+
+	assert.Equal(t, "\n[X] 1 expired exceptions in exceptions.yaml, renew or remove them:\n"+
+		"- CONTAINERS_MISSING_LIVENESSPROBE_KEY [file: legacy/**] (owner: web-team, ticket: WEB-42, expires: 2026-01-31)\n",
+		getExpiredExceptionsText(expiredExceptions, "exceptions.yaml"))
+}
+
+type RendererMock struct {
+	mock.Mock
+}
+
+func (rm *RendererMock) Render(source renderer.Source) ([]byte, error) {
+	args := rm.Called(source)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func TestRenderSources(t *testing.T) {
+	t.Run("should replace the files of a chart with its rendered resources", func(t *testing.T) {
+		chartDir := pathFromRoot("pkg/renderer/test_fixtures/charts/web")
+		rendererMock := &RendererMock{}
+		rendererMock.On("Render", renderer.Source{Dir: chartDir, Engine: renderer.EngineHelm}).Return([]byte("apiVersion: v1\nkind: Service\n"), nil)
+
+		filesPaths, renderedFilesPaths, err := renderSources(rendererMock, []string{
+			filepath.Join(chartDir, "Chart.yaml"),
+			filepath.Join(chartDir, "templates", "deployment.yaml"),
+			pathFromRoot("pkg/renderer/test_fixtures/manifests/configmap.yaml"),
+		})
+		defer removeFiles(renderedFilesPaths)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(renderedFilesPaths))
+		assert.Equal(t, []string{pathFromRoot("pkg/renderer/test_fixtures/manifests/configmap.yaml"), renderedFilesPaths[0]}, filesPaths)
+		content, _ := os.ReadFile(renderedFilesPaths[0])
+		assert.Equal(t, "apiVersion: v1\nkind: Service\n", string(content))
+	})
+
+	t.Run("should return the rendering error", func(t *testing.T) {
+		rendererMock := &RendererMock{}
+		rendererMock.On("Render", mock.Anything).Return([]byte(nil), errors.New("failed rendering web: helm is not installed"))
+
+		_, renderedFilesPaths, err := renderSources(rendererMock, []string{pathFromRoot("pkg/renderer/test_fixtures/charts/web/Chart.yaml")})
+
+		assert.EqualError(t, err, "failed rendering web: helm is not installed")
+		assert.Empty(t, renderedFilesPaths)
+	})
 }
 
 func TestTestCommandEmptyDir(t *testing.T) {
@@ -829,6 +901,39 @@ func TestTestCommandOnlyK8sFiles(t *testing.T) {
 	k8sValidatorMock.AssertCalled(t, "GetK8sFiles", mock.Anything, 100)
 }
 
+func TestTestCommandMultiplePolicies(t *testing.T) {
+	setup()
+	productionPolicy := policy_factory.Policy{Name: "Production", Rules: testingPolicy.Rules[:1]}
+	_ = test(ctx, []string{"valid/path"}, &TestCommandData{Output: "json", Policy: productionPolicy, Policies: []policy_factory.Policy{testingPolicy, productionPolicy}, GatingPolicyIndex: 1})
+
+	for _, policy := range []policy_factory.Policy{testingPolicy, productionPolicy} {
+		mockedEvaluator.AssertCalled(t, "Evaluate", evaluation.PolicyCheckData{
+			FilesConfigurations: filesConfigurations,
+			IsInteractiveMode:   false,
+			PolicyName:          policy.Name,
+			Policy:              policy,
+		})
+	}
+	// the files are extracted and validated once for all the policies
+	k8sValidatorMock.AssertNumberOfCalls(t, "ValidateResources", 1)
+	mockedEvaluator.AssertNumberOfCalls(t, "Evaluate", 2)
+	// the run is recorded once, with the results of the gating policy
+	mockedEvaluator.AssertNumberOfCalls(t, "SendEvaluationResult", 1)
+	mockedEvaluator.AssertCalled(t, "SendEvaluationResult", mock.MatchedBy(func(evaluationRequestData evaluation.EvaluationRequestData) bool {
+		return evaluationRequestData.PolicyName == productionPolicy.Name
+	}))
+}
+
+func TestTestCommandMultiplePoliciesWithTheSameName(t *testing.T) {
+	setup()
+	extendedTestingPolicy := policy_factory.Policy{Name: testingPolicy.Name, Rules: testingPolicy.Rules[:1]}
+	_ = test(ctx, []string{"valid/path"}, &TestCommandData{Output: "json", Policy: extendedTestingPolicy, Policies: []policy_factory.Policy{testingPolicy, extendedTestingPolicy}, GatingPolicyIndex: 1})
+
+	// the gating policy is the resolved policy at the gating index, not every policy with its name
+	mockedEvaluator.AssertNumberOfCalls(t, "Evaluate", 2)
+	mockedEvaluator.AssertNumberOfCalls(t, "SendEvaluationResult", 1)
+}
+
 func TestShouldDisplaySpinner(t *testing.T) {
 	defaultCaseSpinner := shouldDisplaySpinner(false, true, "")
 	assert.True(t, defaultCaseSpinner)
@@ -928,6 +1033,30 @@ func test_testCommand_save_results_flag(t *testing.T, ctx *TestCommandContext) {
 	assert.EqualError(t, err, "open ../non-exsisted-dir/test.json: no such file or directory")
 }
 
+func test_testCommand_fail_on_flag_validation(t *testing.T, ctx *TestCommandContext) {
+	for _, value := range []string{"critical", "high", "medium", "low", "info"} {
+		flags := TestCommandFlags{FailOn: value}
+		err := flags.Validate()
+		assert.NoError(t, err)
+	}
+
+	err := executeTestCommand(ctx, []string{"8/*", "--fail-on=severe"})
+	assert.EqualError(t, err, "invalid --fail-on option - \"severe\"\nValid fail-on values are - critical, high, medium, low, info")
+}
+
+func test_testCommand_policy_flag_validation(t *testing.T, ctx *TestCommandContext) {
+	flags := TestCommandFlags{PolicyName: "Starter, Production", GatingPolicy: "Production"}
+	assert.NoError(t, flags.Validate())
+	assert.Equal(t, []string{"Starter", "Production"}, flags.PolicyNames())
+	assert.Equal(t, []string{""}, (&TestCommandFlags{}).PolicyNames())
+
+	err := executeTestCommand(ctx, []string{"8/*", "--policy=Starter,Starter"})
+	assert.EqualError(t, err, "invalid --policy option - \"Starter,Starter\"\nPolicy Starter is set more than once")
+
+	err = executeTestCommand(ctx, []string{"8/*", "--policy=Starter,Production", "--gating-policy=Staging"})
+	assert.EqualError(t, err, "invalid --gating-policy option - \"Staging\"\nThe gating policy must be one of the policies of the --policy flag")
+}
+
 func newFilesConfigurationsChan(path string) chan *extractor.FileConfigurations {
 	filesConfigurationsChan := make(chan *extractor.FileConfigurations, 1)
 
@@ -0,0 +1,184 @@
+// This package converts admission policies written for the cluster (ValidatingAdmissionPolicy, Gatekeeper constraints)
+// to datree custom rules, so the same policies can run in CI
+
+package admissionPolicies
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPolicyName is the policy created when the policies file contains only admission policies manifests
+const DefaultPolicyName = "AdmissionPolicies"
+
+type ObjectMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type manifest struct {
+	ApiVersion string     `json:"apiVersion"`
+	Kind       string     `json:"kind"`
+	Metadata   ObjectMeta `json:"metadata"`
+}
+
+// MergeIntoPoliciesYaml returns the policies file content with the admission policies manifests in it converted to custom rules.
+// The content is returned as is when it doesn't contain any admission policy
+func MergeIntoPoliciesYaml(content []byte) ([]byte, error) {
+	documents, err := splitDocuments(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var policiesDocument map[string]interface{}
+	var manifests []map[string]interface{}
+	for _, document := range documents {
+		documentManifest, err := getManifest(document)
+		if err != nil {
+			return nil, err
+		}
+
+		// the datree policies document is the one without a kind
+		if documentManifest.Kind == "" && policiesDocument == nil {
+			policiesDocument = document
+		} else {
+			manifests = append(manifests, document)
+		}
+	}
+
+	var customRules []*defaultPolicies.CustomRule
+	for _, convert := range []func([]map[string]interface{}) ([]*defaultPolicies.CustomRule, error){convertValidatingAdmissionPolicies, convertGatekeeperConstraints} {
+		convertedCustomRules, err := convert(manifests)
+		if err != nil {
+			return nil, err
+		}
+		customRules = append(customRules, convertedCustomRules...)
+	}
+
+	if len(customRules) == 0 {
+		return content, nil
+	}
+
+	if policiesDocument == nil {
+		policiesDocument = newDefaultPoliciesDocument(customRules)
+	} else if err := addToDefaultPolicy(policiesDocument, customRules); err != nil {
+		return nil, err
+	}
+
+	existingCustomRules, _ := policiesDocument["customRules"].([]interface{})
+	for _, customRule := range customRules {
+		existingCustomRules = append(existingCustomRules, customRule)
+	}
+	policiesDocument["customRules"] = existingCustomRules
+
+	return json.Marshal(policiesDocument)
+}
+
+func newDefaultPoliciesDocument(customRules []*defaultPolicies.CustomRule) map[string]interface{} {
+	var rules []interface{}
+	for _, customRule := range customRules {
+		rules = append(rules, newPolicyRule(customRule))
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"policies": []interface{}{map[string]interface{}{
+			"name":      DefaultPolicyName,
+			"isDefault": true,
+			"rules":     rules,
+		}},
+	}
+}
+
+// addToDefaultPolicy adds the custom rules that no policy references yet to the default policy of the policies document,
+// or to its first policy when none is marked as default, so the converted admission policies are evaluated
+func addToDefaultPolicy(policiesDocument map[string]interface{}, customRules []*defaultPolicies.CustomRule) error {
+	policies, _ := policiesDocument["policies"].([]interface{})
+
+	var defaultPolicy map[string]interface{}
+	referencedIdentifiers := make(map[string]bool)
+	for _, policy := range policies {
+		policyMap, ok := policy.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if isDefault, _ := policyMap["isDefault"].(bool); defaultPolicy == nil || isDefault && defaultPolicy["isDefault"] != true {
+			defaultPolicy = policyMap
+		}
+		rules, _ := policyMap["rules"].([]interface{})
+		for _, rule := range rules {
+			if ruleMap, ok := rule.(map[string]interface{}); ok {
+				if identifier, ok := ruleMap["identifier"].(string); ok {
+					referencedIdentifiers[identifier] = true
+				}
+			}
+		}
+	}
+	if defaultPolicy == nil {
+		return errors.New("the policies file must contain at least one policy to add the admission policies to")
+	}
+
+	rules, _ := defaultPolicy["rules"].([]interface{})
+	for _, customRule := range customRules {
+		if !referencedIdentifiers[customRule.Identifier] {
+			rules = append(rules, newPolicyRule(customRule))
+		}
+	}
+	defaultPolicy["rules"] = rules
+	return nil
+}
+
+func newPolicyRule(customRule *defaultPolicies.CustomRule) map[string]interface{} {
+	return map[string]interface{}{
+		"identifier":       customRule.Identifier,
+		"messageOnFailure": customRule.DefaultMessageOnFailure,
+	}
+}
+
+var nonIdentifierCharacters = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// getRuleIdentifier converts a kubernetes name (my-policy.example.com) to a rule identifier (MY_POLICY.EXAMPLE.COM)
+func getRuleIdentifier(name string) string {
+	identifier := nonIdentifierCharacters.ReplaceAllString(name, "_")
+	return strings.ToUpper(strings.Trim(identifier, "_"))
+}
+
+func splitDocuments(content []byte) ([]map[string]interface{}, error) {
+	var documents []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document map[string]interface{}
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if document != nil {
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+func getManifest(document map[string]interface{}) (manifest, error) {
+	var documentManifest manifest
+	err := convertDocument(document, &documentManifest)
+	return documentManifest, err
+}
+
+func convertDocument(document map[string]interface{}, out interface{}) error {
+	b, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,292 @@
+// This file converts Gatekeeper ConstraintTemplates and their Constraints to datree custom rules backed by rego
+
+package admissionPolicies
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+)
+
+const (
+	constraintTemplateKind   = "ConstraintTemplate"
+	templatesGatekeeperApi   = "templates.gatekeeper.sh/"
+	constraintsGatekeeperApi = "constraints.gatekeeper.sh/"
+)
+
+type ConstraintTemplate struct {
+	Metadata ObjectMeta             `json:"metadata"`
+	Spec     ConstraintTemplateSpec `json:"spec"`
+}
+
+type ConstraintTemplateSpec struct {
+	CRD struct {
+		Spec struct {
+			Names struct {
+				Kind string `json:"kind"`
+			} `json:"names"`
+		} `json:"spec"`
+	} `json:"crd"`
+	Targets []ConstraintTemplateTarget `json:"targets"`
+}
+
+type ConstraintTemplateTarget struct {
+	Target string   `json:"target"`
+	Rego   string   `json:"rego"`
+	Libs   []string `json:"libs"`
+}
+
+type Constraint struct {
+	Kind     string         `json:"kind"`
+	Metadata ObjectMeta     `json:"metadata"`
+	Spec     ConstraintSpec `json:"spec"`
+}
+
+type ConstraintSpec struct {
+	Match      ConstraintMatch        `json:"match"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+type ConstraintMatch struct {
+	Kinds              []ConstraintMatchKinds `json:"kinds"`
+	Namespaces         []string               `json:"namespaces"`
+	ExcludedNamespaces []string               `json:"excludedNamespaces"`
+	Name               string                 `json:"name"`
+}
+
+type ConstraintMatchKinds struct {
+	ApiGroups []string `json:"apiGroups"`
+	Kinds     []string `json:"kinds"`
+}
+
+// namespace of the resources that don't specify one, as kubectl would apply them
+const defaultNamespace = "default"
+
+// gatekeeperRegoCode runs the template's violation rule with the input gatekeeper builds from an admission review,
+// the resource is input.review.object and the constraint parameters are input.parameters
+const gatekeeperRegoCode = `package datree.gatekeeper
+
+api_group = group {
+	parts := split(input.apiVersion, "/")
+	count(parts) == 2
+	group := parts[0]
+} else = ""
+
+api_version = version {
+	parts := split(input.apiVersion, "/")
+	version := parts[count(parts) - 1]
+} else = ""
+
+review := {
+	"object": input,
+	"kind": {"group": api_group, "version": api_version, "kind": object.get(input, "kind", "")},
+	"name": object.get(object.get(input, "metadata", {}), "name", ""),
+	"namespace": object.get(object.get(input, "metadata", {}), "namespace", ""),
+	"operation": "CREATE",
+}
+
+violation[result] {
+	gatekeeper_input := {"review": review, "parameters": %s}
+	result := data.%s.violation[_] with input as gatekeeper_input
+}
+`
+
+// convertGatekeeperConstraints converts each Constraint to a custom rule that runs the rego of its ConstraintTemplate
+func convertGatekeeperConstraints(documents []map[string]interface{}) ([]*defaultPolicies.CustomRule, error) {
+	templatesByKind := make(map[string]*ConstraintTemplate)
+	var constraints []*Constraint
+
+	for _, document := range documents {
+		documentManifest, err := getManifest(document)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(documentManifest.ApiVersion, templatesGatekeeperApi) && documentManifest.Kind == constraintTemplateKind:
+			var template ConstraintTemplate
+			if err = convertDocument(document, &template); err != nil {
+				return nil, err
+			}
+			templatesByKind[template.Spec.CRD.Spec.Names.Kind] = &template
+		case strings.HasPrefix(documentManifest.ApiVersion, constraintsGatekeeperApi):
+			var constraint Constraint
+			if err = convertDocument(document, &constraint); err != nil {
+				return nil, err
+			}
+			constraints = append(constraints, &constraint)
+		}
+	}
+
+	var customRules []*defaultPolicies.CustomRule
+	for _, constraint := range constraints {
+		template, ok := templatesByKind[constraint.Kind]
+		if !ok {
+			return nil, fmt.Errorf("Constraint %s: ConstraintTemplate of kind %s was not found", constraint.Metadata.Name, constraint.Kind)
+		}
+
+		regoDefinition, err := getGatekeeperRegoDefinition(template, constraint)
+		if err != nil {
+			return nil, err
+		}
+
+		then := map[string]interface{}{"regoDefinition": regoDefinition}
+		var schema interface{} = then
+		if matchSchema := getConstraintMatchSchema(constraint.Spec.Match); matchSchema != nil {
+			schema = map[string]interface{}{"if": matchSchema, "then": then}
+		}
+
+		customRules = append(customRules, &defaultPolicies.CustomRule{
+			Identifier:              getRuleIdentifier(constraint.Kind + "_" + constraint.Metadata.Name),
+			Name:                    constraint.Kind + " " + constraint.Metadata.Name + " [GATEKEEPER CONSTRAINT]",
+			DefaultMessageOnFailure: fmt.Sprintf("Gatekeeper constraint %s/%s denied the resource", constraint.Kind, constraint.Metadata.Name),
+			Schema:                  schema,
+		})
+	}
+	return customRules, nil
+}
+
+func getGatekeeperRegoDefinition(template *ConstraintTemplate, constraint *Constraint) (map[string]interface{}, error) {
+	var target *ConstraintTemplateTarget
+	for i := range template.Spec.Targets {
+		if template.Spec.Targets[i].Rego != "" {
+			target = &template.Spec.Targets[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("ConstraintTemplate %s: a target with rego is required", template.Metadata.Name)
+	}
+
+	templatePackage, err := getRegoPackage(target.Rego)
+	if err != nil {
+		return nil, fmt.Errorf("ConstraintTemplate %s: %s", template.Metadata.Name, err.Error())
+	}
+
+	parameters := constraint.Spec.Parameters
+	if parameters == nil {
+		parameters = map[string]interface{}{}
+	}
+	// a json value is a valid rego term
+	parametersJson, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var libs []interface{}
+	libs = append(libs, target.Rego)
+	for _, lib := range target.Libs {
+		libs = append(libs, lib)
+	}
+
+	return map[string]interface{}{
+		"libs": libs,
+		"code": fmt.Sprintf(gatekeeperRegoCode, parametersJson, templatePackage),
+	}, nil
+}
+
+var regoPackageRegex = regexp.MustCompile(`(?m)^\s*package\s+([^\s#]+)`)
+
+func getRegoPackage(regoCode string) (string, error) {
+	match := regoPackageRegex.FindStringSubmatch(regoCode)
+	if match == nil {
+		return "", fmt.Errorf("rego code must have a package")
+	}
+	return match[1], nil
+}
+
+// getConstraintMatchSchema returns a schema that matches the resources of the constraint, or nil to match every resource
+func getConstraintMatchSchema(match ConstraintMatch) map[string]interface{} {
+	var conditions []interface{}
+
+	if len(match.Kinds) > 0 {
+		var kindsSchemas []interface{}
+		for _, matchKinds := range match.Kinds {
+			kindsSchemas = append(kindsSchemas, getConstraintMatchKindsSchema(matchKinds))
+		}
+		conditions = append(conditions, map[string]interface{}{"anyOf": kindsSchemas})
+	}
+
+	if len(match.Namespaces) > 0 {
+		conditions = append(conditions, getNamespaceSchema(match.Namespaces))
+	}
+
+	if len(match.ExcludedNamespaces) > 0 {
+		conditions = append(conditions, map[string]interface{}{"not": getNamespaceSchema(match.ExcludedNamespaces)})
+	}
+
+	if match.Name != "" {
+		conditions = append(conditions, map[string]interface{}{
+			"required": []interface{}{"metadata"},
+			"properties": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"required":   []interface{}{"name"},
+					"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string", "pattern": getGlobsPattern([]string{match.Name})}},
+				},
+			},
+		})
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+	return map[string]interface{}{"allOf": conditions}
+}
+
+func getConstraintMatchKindsSchema(matchKinds ConstraintMatchKinds) map[string]interface{} {
+	properties := make(map[string]interface{})
+
+	if len(matchKinds.Kinds) > 0 && !containsWildcard(matchKinds.Kinds) {
+		var kinds []interface{}
+		for _, kind := range matchKinds.Kinds {
+			kinds = append(kinds, kind)
+		}
+		properties["kind"] = map[string]interface{}{"type": "string", "enum": kinds}
+	}
+	if apiVersionPattern := getApiVersionPattern(matchKinds.ApiGroups, nil); apiVersionPattern != "" {
+		properties["apiVersion"] = map[string]interface{}{"type": "string", "pattern": apiVersionPattern}
+	}
+
+	return map[string]interface{}{
+		"required":   []interface{}{"apiVersion", "kind"},
+		"properties": properties,
+	}
+}
+
+// getNamespaceSchema matches resources in one of the namespaces, resources without a namespace are in the default namespace
+func getNamespaceSchema(namespaces []string) map[string]interface{} {
+	namespacesPattern := getGlobsPattern(namespaces)
+	namespaceInNamespaces := map[string]interface{}{
+		"required": []interface{}{"metadata"},
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"required":   []interface{}{"namespace"},
+				"properties": map[string]interface{}{"namespace": map[string]interface{}{"type": "string", "pattern": namespacesPattern}},
+			},
+		},
+	}
+
+	if !regexp.MustCompile(namespacesPattern).MatchString(defaultNamespace) {
+		return namespaceInNamespaces
+	}
+
+	withoutNamespace := map[string]interface{}{
+		"not": map[string]interface{}{
+			"required":   []interface{}{"metadata"},
+			"properties": map[string]interface{}{"metadata": map[string]interface{}{"required": []interface{}{"namespace"}}},
+		},
+	}
+	return map[string]interface{}{"anyOf": []interface{}{namespaceInNamespaces, withoutNamespace}}
+}
+
+// getGlobsPattern matches any of the given values, gatekeeper supports a * wildcard as a prefix or a suffix
+func getGlobsPattern(globs []string) string {
+	var patterns []string
+	for _, glob := range globs {
+		patterns = append(patterns, strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*"))
+	}
+	return "^(" + strings.Join(patterns, "|") + ")$"
+}
@@ -0,0 +1,102 @@
+package admissionPolicies
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed test_fixtures/gatekeeperConstraint.yaml
+var gatekeeperConstraint string
+
+//go:embed test_fixtures/gatekeeperConstraintWithoutTemplate.yaml
+var gatekeeperConstraintWithoutTemplate string
+
+const deploymentMissingLabels = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    owner: team-a
+`
+
+const deploymentInExcludedNamespace = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: kube-system
+`
+
+const deploymentWithLabels = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: production
+  labels:
+    owner: team-a
+    billing: team-a
+`
+
+func TestMergeIntoPoliciesYamlWithGatekeeperConstraint(t *testing.T) {
+	policies := mergeAndUnmarshal(t, gatekeeperConstraint)
+
+	assert.Equal(t, []defaultPolicies.Rule{{Identifier: "K8SREQUIREDLABELS_DEPLOYMENTS_MUST_HAVE_OWNER", MessageOnFailure: "Gatekeeper constraint K8sRequiredLabels/deployments-must-have-owner denied the resource"}}, policies.Policies[0].Rules)
+	assert.Equal(t, 1, len(policies.CustomRules))
+	assert.Equal(t, "K8sRequiredLabels deployments-must-have-owner [GATEKEEPER CONSTRAINT]", policies.CustomRules[0].Name)
+
+	validator := jsonSchemaValidator.New()
+	ruleSchema := getRuleSchemaJson(t, policies.CustomRules[0])
+
+	t.Run("failing resource reports the violation message", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, deploymentMissingLabels)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(errorsResult))
+		assert.Equal(t, "Deployment web must have the labels: {\"billing\"}", errorsResult[0].Error)
+	})
+
+	t.Run("resource in an excluded namespace", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, deploymentInExcludedNamespace)
+		assert.NoError(t, err)
+		assert.Empty(t, errorsResult)
+	})
+
+	t.Run("passing resource", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, deploymentWithLabels)
+		assert.NoError(t, err)
+		assert.Empty(t, errorsResult)
+	})
+
+	t.Run("resource that doesn't match the kinds", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, serviceNotMatched)
+		assert.NoError(t, err)
+		assert.Empty(t, errorsResult)
+	})
+}
+
+func TestMergeIntoPoliciesYamlWithConstraintWithoutTemplate(t *testing.T) {
+	_, err := MergeIntoPoliciesYaml([]byte(gatekeeperConstraintWithoutTemplate))
+
+	assert.EqualError(t, err, "Constraint deployments-must-have-owner: ConstraintTemplate of kind K8sRequiredLabels was not found")
+}
+
+func TestGetNamespaceSchema(t *testing.T) {
+	validator := jsonSchemaValidator.New()
+	getErrors := func(namespaces []string, resource string) int {
+		schema := getNamespaceSchema(namespaces)
+		errorsResult, err := validator.ValidateYamlSchema(toJson(t, schema), resource)
+		assert.NoError(t, err)
+		return len(errorsResult)
+	}
+
+	assert.Equal(t, 0, getErrors([]string{"kube-*"}, deploymentInExcludedNamespace))
+	assert.NotEqual(t, 0, getErrors([]string{"kube-*"}, deploymentWithLabels))
+	// a resource without a namespace is in the default namespace
+	assert.Equal(t, 0, getErrors([]string{"default"}, deploymentMissingLabels))
+	assert.NotEqual(t, 0, getErrors([]string{"production"}, deploymentMissingLabels))
+}
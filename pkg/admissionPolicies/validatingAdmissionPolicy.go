@@ -0,0 +1,445 @@
+// This file converts ValidatingAdmissionPolicy manifests to datree custom rules backed by CEL
+
+package admissionPolicies
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+)
+
+const (
+	validatingAdmissionPolicyKind        = "ValidatingAdmissionPolicy"
+	validatingAdmissionPolicyBindingKind = "ValidatingAdmissionPolicyBinding"
+	admissionRegistrationGroup           = "admissionregistration.k8s.io/"
+	denyValidationAction                 = "Deny"
+)
+
+type ValidatingAdmissionPolicy struct {
+	Metadata ObjectMeta                    `json:"metadata"`
+	Spec     ValidatingAdmissionPolicySpec `json:"spec"`
+}
+
+type ValidatingAdmissionPolicySpec struct {
+	ParamKind        *ParamKind       `json:"paramKind"`
+	MatchConstraints MatchResources   `json:"matchConstraints"`
+	Validations      []Validation     `json:"validations"`
+	MatchConditions  []MatchCondition `json:"matchConditions"`
+}
+
+type ParamKind struct {
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+type MatchResources struct {
+	NamespaceSelector    *LabelSelector            `json:"namespaceSelector"`
+	ObjectSelector       *LabelSelector            `json:"objectSelector"`
+	ResourceRules        []NamedRuleWithOperations `json:"resourceRules"`
+	ExcludeResourceRules []NamedRuleWithOperations `json:"excludeResourceRules"`
+}
+
+type LabelSelector struct {
+	MatchLabels      map[string]string          `json:"matchLabels"`
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions"`
+}
+
+type LabelSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+type NamedRuleWithOperations struct {
+	ApiGroups   []string `json:"apiGroups"`
+	ApiVersions []string `json:"apiVersions"`
+	Resources   []string `json:"resources"`
+}
+
+type Validation struct {
+	Expression        string `json:"expression"`
+	Message           string `json:"message,omitempty"`
+	MessageExpression string `json:"messageExpression,omitempty"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+type MatchCondition struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+type ValidatingAdmissionPolicyBinding struct {
+	Metadata ObjectMeta                           `json:"metadata"`
+	Spec     ValidatingAdmissionPolicyBindingSpec `json:"spec"`
+}
+
+type ValidatingAdmissionPolicyBindingSpec struct {
+	PolicyName        string          `json:"policyName"`
+	ParamRef          *ParamRef       `json:"paramRef"`
+	MatchResources    *MatchResources `json:"matchResources"`
+	ValidationActions []string        `json:"validationActions"`
+}
+
+type ParamRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// convertValidatingAdmissionPolicies converts the ValidatingAdmissionPolicy manifests to custom rules,
+// with the params of each policy taken from the object its binding references
+func convertValidatingAdmissionPolicies(documents []map[string]interface{}) ([]*defaultPolicies.CustomRule, error) {
+	var policies []*ValidatingAdmissionPolicy
+	var bindings []*ValidatingAdmissionPolicyBinding
+
+	for _, document := range documents {
+		documentManifest, err := getManifest(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(documentManifest.ApiVersion, admissionRegistrationGroup) {
+			continue
+		}
+
+		switch documentManifest.Kind {
+		case validatingAdmissionPolicyKind:
+			var policy ValidatingAdmissionPolicy
+			if err = convertDocument(document, &policy); err != nil {
+				return nil, err
+			}
+			policies = append(policies, &policy)
+		case validatingAdmissionPolicyBindingKind:
+			var binding ValidatingAdmissionPolicyBinding
+			if err = convertDocument(document, &binding); err != nil {
+				return nil, err
+			}
+			bindings = append(bindings, &binding)
+		}
+	}
+
+	return convertToCustomRules(policies, bindings, documents)
+}
+
+func convertToCustomRules(policies []*ValidatingAdmissionPolicy, bindings []*ValidatingAdmissionPolicyBinding, paramsObjects []map[string]interface{}) ([]*defaultPolicies.CustomRule, error) {
+	var customRules []*defaultPolicies.CustomRule
+	for _, policy := range policies {
+		if policy.Metadata.Name == "" {
+			return nil, fmt.Errorf("ValidatingAdmissionPolicy metadata.name can't be empty")
+		}
+		if len(policy.Spec.Validations) == 0 {
+			return nil, fmt.Errorf("ValidatingAdmissionPolicy %s: spec.validations can't be empty", policy.Metadata.Name)
+		}
+		if len(policy.Spec.MatchConditions) > 0 {
+			return nil, fmt.Errorf("ValidatingAdmissionPolicy %s: spec.matchConditions are not supported", policy.Metadata.Name)
+		}
+
+		policyBinding, err := getPolicyBinding(policy, bindings)
+		if err != nil {
+			return nil, err
+		}
+
+		params, err := getPolicyParams(policy, policyBinding, paramsObjects)
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := getRuleSchema(policy, policyBinding, params)
+		if err != nil {
+			return nil, err
+		}
+
+		customRules = append(customRules, &defaultPolicies.CustomRule{
+			Identifier:              getRuleIdentifier(policy.Metadata.Name),
+			Name:                    policy.Metadata.Name + " [VALIDATING ADMISSION POLICY]",
+			DefaultMessageOnFailure: getDefaultMessageOnFailure(policy),
+			Schema:                  schema,
+		})
+	}
+	return customRules, nil
+}
+
+// getPolicyBinding returns the binding of the policy, or nil when the policy isn't bound.
+// The rules fail the check like a Deny binding does, so bindings that only warn or audit are not supported
+func getPolicyBinding(policy *ValidatingAdmissionPolicy, bindings []*ValidatingAdmissionPolicyBinding) (*ValidatingAdmissionPolicyBinding, error) {
+	var policyBinding *ValidatingAdmissionPolicyBinding
+	for _, binding := range bindings {
+		if binding.Spec.PolicyName != policy.Metadata.Name {
+			continue
+		}
+		if policyBinding != nil {
+			return nil, fmt.Errorf("ValidatingAdmissionPolicy %s: only one ValidatingAdmissionPolicyBinding per policy is supported", policy.Metadata.Name)
+		}
+		policyBinding = binding
+	}
+
+	if policyBinding != nil && len(policyBinding.Spec.ValidationActions) > 0 && !containsValue(policyBinding.Spec.ValidationActions, denyValidationAction) {
+		return nil, fmt.Errorf("ValidatingAdmissionPolicyBinding %s: validationActions %s are not supported, only %s is", policyBinding.Metadata.Name, strings.Join(policyBinding.Spec.ValidationActions, ", "), denyValidationAction)
+	}
+	return policyBinding, nil
+}
+
+// getPolicyParams returns the params object referenced by the policy binding, or nil when the policy has no paramKind
+func getPolicyParams(policy *ValidatingAdmissionPolicy, policyBinding *ValidatingAdmissionPolicyBinding, paramsObjects []map[string]interface{}) (map[string]interface{}, error) {
+	if policy.Spec.ParamKind == nil {
+		return nil, nil
+	}
+
+	if policyBinding == nil || policyBinding.Spec.ParamRef == nil || policyBinding.Spec.ParamRef.Name == "" {
+		return nil, fmt.Errorf("ValidatingAdmissionPolicy %s: a ValidatingAdmissionPolicyBinding with paramRef.name is required since the policy has a paramKind", policy.Metadata.Name)
+	}
+
+	paramRef := policyBinding.Spec.ParamRef
+	for _, paramsObject := range paramsObjects {
+		paramsManifest, err := getManifest(paramsObject)
+		if err != nil {
+			return nil, err
+		}
+
+		if paramsManifest.ApiVersion == policy.Spec.ParamKind.ApiVersion && paramsManifest.Kind == policy.Spec.ParamKind.Kind &&
+			paramsManifest.Metadata.Name == paramRef.Name && (paramRef.Namespace == "" || paramsManifest.Metadata.Namespace == paramRef.Namespace) {
+			return paramsObject, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ValidatingAdmissionPolicy %s: params object %s of kind %s referenced by binding %s was not found", policy.Metadata.Name, paramRef.Name, policy.Spec.ParamKind.Kind, policyBinding.Metadata.Name)
+}
+
+func getDefaultMessageOnFailure(policy *ValidatingAdmissionPolicy) string {
+	if len(policy.Spec.Validations) == 1 && policy.Spec.Validations[0].Message != "" {
+		return policy.Spec.Validations[0].Message
+	}
+	return fmt.Sprintf("ValidatingAdmissionPolicy %s denied the resource", policy.Metadata.Name)
+}
+
+// getRuleSchema runs the validations on the resources matched by both the policy's matchConstraints and the binding's matchResources
+func getRuleSchema(policy *ValidatingAdmissionPolicy, policyBinding *ValidatingAdmissionPolicyBinding, params map[string]interface{}) (map[string]interface{}, error) {
+	var CELDefinition []interface{}
+	for _, validation := range policy.Spec.Validations {
+		CELDefinition = append(CELDefinition, map[string]interface{}{
+			"expression":        validation.Expression,
+			"message":           validation.Message,
+			"messageExpression": validation.MessageExpression,
+			"reason":            validation.Reason,
+		})
+	}
+
+	then := map[string]interface{}{"CELDefinition": CELDefinition}
+	if params != nil {
+		then["CELParams"] = params
+	}
+
+	matchConstraintsSchema, err := getMatchConstraintsSchema(policy.Spec.MatchConstraints)
+	if err != nil {
+		return nil, fmt.Errorf("ValidatingAdmissionPolicy %s: %s", policy.Metadata.Name, err)
+	}
+
+	if policyBinding != nil && policyBinding.Spec.MatchResources != nil {
+		bindingMatchSchema, err := getMatchConstraintsSchema(*policyBinding.Spec.MatchResources)
+		if err != nil {
+			return nil, fmt.Errorf("ValidatingAdmissionPolicyBinding %s: %s", policyBinding.Metadata.Name, err)
+		}
+		if matchConstraintsSchema == nil {
+			matchConstraintsSchema = bindingMatchSchema
+		} else if bindingMatchSchema != nil {
+			matchConstraintsSchema = map[string]interface{}{"allOf": []interface{}{matchConstraintsSchema, bindingMatchSchema}}
+		}
+	}
+
+	if matchConstraintsSchema == nil {
+		return then, nil
+	}
+	return map[string]interface{}{
+		"if":   matchConstraintsSchema,
+		"then": then,
+	}, nil
+}
+
+// getMatchConstraintsSchema returns a schema that matches the resources of the given rules, or nil to match every resource.
+// The labels of the namespaces aren't known without a cluster, so a namespaceSelector can't be matched
+func getMatchConstraintsSchema(matchConstraints MatchResources) (map[string]interface{}, error) {
+	if matchConstraints.NamespaceSelector != nil && !matchConstraints.NamespaceSelector.isEmpty() {
+		return nil, fmt.Errorf("namespaceSelector is not supported")
+	}
+
+	var conditions []interface{}
+
+	if matchConstraints.ObjectSelector != nil {
+		objectSelectorConditions, err := getLabelSelectorConditions(*matchConstraints.ObjectSelector)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, objectSelectorConditions...)
+	}
+
+	if len(matchConstraints.ResourceRules) > 0 {
+		var resourceRulesSchemas []interface{}
+		for _, resourceRule := range matchConstraints.ResourceRules {
+			resourceRulesSchemas = append(resourceRulesSchemas, getResourceRuleSchema(resourceRule))
+		}
+		conditions = append(conditions, map[string]interface{}{"anyOf": resourceRulesSchemas})
+	}
+
+	for _, excludeResourceRule := range matchConstraints.ExcludeResourceRules {
+		conditions = append(conditions, map[string]interface{}{"not": getResourceRuleSchema(excludeResourceRule)})
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return map[string]interface{}{"allOf": conditions}, nil
+}
+
+func (selector LabelSelector) isEmpty() bool {
+	return len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0
+}
+
+// getLabelSelectorConditions returns the schemas that match the labels of the selector, like the selectors of kubernetes
+func getLabelSelectorConditions(selector LabelSelector) ([]interface{}, error) {
+	var conditions []interface{}
+
+	labelKeys := make([]string, 0, len(selector.MatchLabels))
+	for labelKey := range selector.MatchLabels {
+		labelKeys = append(labelKeys, labelKey)
+	}
+	sort.Strings(labelKeys)
+	for _, labelKey := range labelKeys {
+		conditions = append(conditions, getLabelSchema(labelKey, map[string]interface{}{"const": selector.MatchLabels[labelKey]}))
+	}
+
+	for _, requirement := range selector.MatchExpressions {
+		var values []interface{}
+		for _, value := range requirement.Values {
+			values = append(values, value)
+		}
+
+		switch requirement.Operator {
+		case "In":
+			conditions = append(conditions, getLabelSchema(requirement.Key, map[string]interface{}{"enum": values}))
+		case "NotIn":
+			conditions = append(conditions, map[string]interface{}{"not": getLabelSchema(requirement.Key, map[string]interface{}{"enum": values})})
+		case "Exists":
+			conditions = append(conditions, getLabelSchema(requirement.Key, nil))
+		case "DoesNotExist":
+			conditions = append(conditions, map[string]interface{}{"not": getLabelSchema(requirement.Key, nil)})
+		default:
+			return nil, fmt.Errorf("objectSelector operator %s is not supported", requirement.Operator)
+		}
+	}
+	return conditions, nil
+}
+
+// getLabelSchema matches resources that have the label, with a value that matches the value schema when it's given
+func getLabelSchema(labelKey string, valueSchema map[string]interface{}) map[string]interface{} {
+	labelsSchema := map[string]interface{}{"required": []interface{}{labelKey}}
+	if valueSchema != nil {
+		labelsSchema["properties"] = map[string]interface{}{labelKey: valueSchema}
+	}
+
+	return map[string]interface{}{
+		"required": []interface{}{"metadata"},
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"required":   []interface{}{"labels"},
+				"properties": map[string]interface{}{"labels": labelsSchema},
+			},
+		},
+	}
+}
+
+func getResourceRuleSchema(resourceRule NamedRuleWithOperations) map[string]interface{} {
+	properties := make(map[string]interface{})
+
+	if kindPattern := getKindPattern(resourceRule.Resources); kindPattern != "" {
+		properties["kind"] = map[string]interface{}{"type": "string", "pattern": kindPattern}
+	}
+	if apiVersionPattern := getApiVersionPattern(resourceRule.ApiGroups, resourceRule.ApiVersions); apiVersionPattern != "" {
+		properties["apiVersion"] = map[string]interface{}{"type": "string", "pattern": apiVersionPattern}
+	}
+
+	return map[string]interface{}{
+		"required":   []interface{}{"apiVersion", "kind"},
+		"properties": properties,
+	}
+}
+
+// getKindPattern matches the kinds of the given resources, resources are plural (deployments) while manifests have a kind (Deployment)
+func getKindPattern(resources []string) string {
+	var kinds []string
+	for _, resource := range resources {
+		if resource == "*" || resource == "*/*" {
+			return ""
+		}
+		// subresources (pods/status) don't have manifests of their own
+		if strings.Contains(resource, "/") {
+			continue
+		}
+		kinds = append(kinds, getKindCandidates(strings.ToLower(resource))...)
+	}
+
+	if len(kinds) == 0 {
+		// none of the resources can be matched by a manifest
+		return "^$"
+	}
+	return "^(?i)(" + strings.Join(kinds, "|") + ")$"
+}
+
+func getKindCandidates(resource string) []string {
+	candidates := []string{regexp.QuoteMeta(resource)}
+	switch {
+	case strings.HasSuffix(resource, "ies"):
+		candidates = append(candidates, regexp.QuoteMeta(strings.TrimSuffix(resource, "ies")+"y"))
+	case strings.HasSuffix(resource, "es"):
+		candidates = append(candidates, regexp.QuoteMeta(strings.TrimSuffix(resource, "es")), regexp.QuoteMeta(strings.TrimSuffix(resource, "s")))
+	case strings.HasSuffix(resource, "s"):
+		candidates = append(candidates, regexp.QuoteMeta(strings.TrimSuffix(resource, "s")))
+	}
+	return candidates
+}
+
+// getApiVersionPattern matches the apiVersion field (group/version, or version for the core group) of the given groups and versions
+func getApiVersionPattern(apiGroups []string, apiVersions []string) string {
+	if len(apiGroups) == 0 || containsWildcard(apiGroups) {
+		if len(apiVersions) == 0 || containsWildcard(apiVersions) {
+			return ""
+		}
+		apiGroups = []string{"*"}
+	}
+
+	versionPattern := "[^/]+"
+	if len(apiVersions) > 0 && !containsWildcard(apiVersions) {
+		var quotedVersions []string
+		for _, apiVersion := range apiVersions {
+			quotedVersions = append(quotedVersions, regexp.QuoteMeta(apiVersion))
+		}
+		versionPattern = "(" + strings.Join(quotedVersions, "|") + ")"
+	}
+
+	var apiVersionPatterns []string
+	for _, apiGroup := range apiGroups {
+		switch apiGroup {
+		case "*":
+			apiVersionPatterns = append(apiVersionPatterns, "([^/]+/)?"+versionPattern)
+		case "":
+			apiVersionPatterns = append(apiVersionPatterns, versionPattern)
+		default:
+			apiVersionPatterns = append(apiVersionPatterns, regexp.QuoteMeta(apiGroup)+"/"+versionPattern)
+		}
+	}
+	return "^(" + strings.Join(apiVersionPatterns, "|") + ")$"
+}
+
+func containsWildcard(values []string) bool {
+	return containsValue(values, "*")
+}
+
+func containsValue(values []string, expectedValue string) bool {
+	for _, value := range values {
+		if value == expectedValue {
+			return true
+		}
+	}
+	return false
+}
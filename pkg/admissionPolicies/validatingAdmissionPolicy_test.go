@@ -0,0 +1,244 @@
+package admissionPolicies
+
+import (
+	_ "embed"
+	"encoding/json"
+	"testing"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed test_fixtures/validatingAdmissionPolicyWithParams.yaml
+var validatingAdmissionPolicyWithParams string
+
+//go:embed test_fixtures/policiesWithValidatingAdmissionPolicy.yaml
+var policiesWithValidatingAdmissionPolicy string
+
+//go:embed test_fixtures/policiesWithAdmissionPolicies.yaml
+var policiesWithAdmissionPolicies string
+
+//go:embed test_fixtures/validatingAdmissionPolicyMissingParams.yaml
+var validatingAdmissionPolicyMissingParams string
+
+//go:embed test_fixtures/validatingAdmissionPolicyWithBindingMatchResources.yaml
+var validatingAdmissionPolicyWithBindingMatchResources string
+
+const deploymentWithTooManyReplicas = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 5
+`
+
+const deploymentWithinLimits = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    billing: team-a
+spec:
+  replicas: 2
+`
+
+const serviceNotMatched = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+func TestMergeIntoPoliciesYamlWithoutPoliciesDocument(t *testing.T) {
+	policies := mergeAndUnmarshal(t, validatingAdmissionPolicyWithParams)
+
+	assert.Equal(t, 1, len(policies.Policies))
+	assert.Equal(t, DefaultPolicyName, policies.Policies[0].Name)
+	assert.True(t, policies.Policies[0].IsDefault)
+	assert.Equal(t, []defaultPolicies.Rule{{Identifier: "REPLICA_LIMIT.EXAMPLE.COM", MessageOnFailure: "ValidatingAdmissionPolicy replica-limit.example.com denied the resource"}}, policies.Policies[0].Rules)
+
+	assert.Equal(t, 1, len(policies.CustomRules))
+	assert.Equal(t, "REPLICA_LIMIT.EXAMPLE.COM", policies.CustomRules[0].Identifier)
+	assert.Equal(t, "replica-limit.example.com [VALIDATING ADMISSION POLICY]", policies.CustomRules[0].Name)
+
+	validator := jsonSchemaValidator.New()
+	ruleSchema := getRuleSchemaJson(t, policies.CustomRules[0])
+
+	t.Run("failing resource reports each failed validation with params", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, deploymentWithTooManyReplicas)
+		assert.NoError(t, err)
+
+		var failureMessages []string
+		for _, errorResult := range errorsResult {
+			failureMessages = append(failureMessages, errorResult.Error)
+		}
+		assert.Equal(t, []string{"replicas must be no greater than 3 (reason: Invalid)", "workloads must have a billing label"}, failureMessages)
+	})
+
+	t.Run("passing resource", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, deploymentWithinLimits)
+		assert.NoError(t, err)
+		assert.Empty(t, errorsResult)
+	})
+
+	t.Run("resource that doesn't match the constraints", func(t *testing.T) {
+		errorsResult, err := validator.ValidateYamlSchema(ruleSchema, serviceNotMatched)
+		assert.NoError(t, err)
+		assert.Empty(t, errorsResult)
+	})
+}
+
+func TestMergeIntoPoliciesYamlWithPoliciesDocument(t *testing.T) {
+	policies := mergeAndUnmarshal(t, policiesWithValidatingAdmissionPolicy)
+
+	assert.Equal(t, 1, len(policies.Policies))
+	assert.Equal(t, "Default", policies.Policies[0].Name)
+	assert.Equal(t, 2, len(policies.Policies[0].Rules))
+
+	assert.Equal(t, 1, len(policies.CustomRules))
+	assert.Equal(t, "NO_HOST_NETWORK.EXAMPLE.COM", policies.CustomRules[0].Identifier)
+	assert.Equal(t, "ValidatingAdmissionPolicy no-host-network.example.com denied the resource", policies.CustomRules[0].DefaultMessageOnFailure)
+}
+
+func TestMergeIntoPoliciesYamlWithPoliciesDocumentAndAdmissionPolicies(t *testing.T) {
+	policies := mergeAndUnmarshal(t, policiesWithAdmissionPolicies)
+
+	assert.Equal(t, 2, len(policies.Policies))
+	assert.Equal(t, "Staging", policies.Policies[0].Name)
+	assert.Equal(t, 1, len(policies.Policies[0].Rules))
+
+	// rules that no policy references are added to the default policy, rules already referenced keep their message
+	assert.Equal(t, "Production", policies.Policies[1].Name)
+	assert.Equal(t, []defaultPolicies.Rule{
+		{Identifier: "CONTAINERS_MISSING_IMAGE_VALUE_VERSION", MessageOnFailure: "Incorrect value for key `image` - specify an image version to avoid unpleasant \"version surprises\" in the future"},
+		{Identifier: "NO_HOST_NETWORK.EXAMPLE.COM", MessageOnFailure: "host network is not allowed in production"},
+		{Identifier: "K8SREQUIREDLABELS_DEPLOYMENTS_MUST_HAVE_OWNER", MessageOnFailure: "Gatekeeper constraint K8sRequiredLabels/deployments-must-have-owner denied the resource"},
+	}, policies.Policies[1].Rules)
+
+	assert.Equal(t, 2, len(policies.CustomRules))
+	assert.Equal(t, "NO_HOST_NETWORK.EXAMPLE.COM", policies.CustomRules[0].Identifier)
+	assert.Equal(t, "K8SREQUIREDLABELS_DEPLOYMENTS_MUST_HAVE_OWNER", policies.CustomRules[1].Identifier)
+}
+
+func TestMergeIntoPoliciesYamlWithPoliciesDocumentWithoutPolicies(t *testing.T) {
+	_, err := MergeIntoPoliciesYaml([]byte("apiVersion: v1\n---\n" + gatekeeperConstraint))
+
+	assert.EqualError(t, err, "the policies file must contain at least one policy to add the admission policies to")
+}
+
+func TestMergeIntoPoliciesYamlWithoutValidatingAdmissionPolicies(t *testing.T) {
+	content := []byte(defaultPolicies.DefaultPoliciesFileContent)
+
+	mergedContent, err := MergeIntoPoliciesYaml(content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, content, mergedContent)
+}
+
+func TestMergeIntoPoliciesYamlWithMissingParams(t *testing.T) {
+	_, err := MergeIntoPoliciesYaml([]byte(validatingAdmissionPolicyMissingParams))
+
+	assert.EqualError(t, err, "ValidatingAdmissionPolicy replica-limit.example.com: params object replica-limit-params of kind ConfigMap referenced by binding replica-limit-binding.example.com was not found")
+}
+
+func TestMergeIntoPoliciesYamlWithBindingMatchResources(t *testing.T) {
+	policies := mergeAndUnmarshal(t, validatingAdmissionPolicyWithBindingMatchResources)
+
+	validator := jsonSchemaValidator.New()
+	ruleSchema := getRuleSchemaJson(t, policies.CustomRules[0])
+
+	tests := []struct {
+		name             string
+		labels           string
+		expectedFailures int
+	}{
+		{name: "resource selected by the binding", labels: "{environment: production}", expectedFailures: 1},
+		{name: "resource selected by the binding with a label value that isn't excluded", labels: "{environment: production, tier: web}", expectedFailures: 1},
+		{name: "resource without the selected label", labels: "{environment: staging}", expectedFailures: 0},
+		{name: "resource with an excluded label value", labels: "{environment: production, tier: canary}", expectedFailures: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  labels: " + tt.labels + "\nspec:\n  replicas: 1\n"
+			errorsResult, err := validator.ValidateYamlSchema(ruleSchema, deployment)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFailures, len(errorsResult))
+		})
+	}
+}
+
+func TestMergeIntoPoliciesYamlWithUnsupportedBinding(t *testing.T) {
+	policy := `
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: replica-minimum.example.com
+spec:
+  validations:
+    - expression: "object.spec.replicas >= 2"
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  name: replica-minimum-binding.example.com
+spec:
+  policyName: replica-minimum.example.com
+`
+
+	t.Run("binding that only warns and audits", func(t *testing.T) {
+		_, err := MergeIntoPoliciesYaml([]byte(policy + "  validationActions: [Warn, Audit]\n"))
+		assert.EqualError(t, err, "ValidatingAdmissionPolicyBinding replica-minimum-binding.example.com: validationActions Warn, Audit are not supported, only Deny is")
+	})
+
+	t.Run("binding with a namespaceSelector", func(t *testing.T) {
+		_, err := MergeIntoPoliciesYaml([]byte(policy + "  validationActions: [Deny]\n  matchResources:\n    namespaceSelector:\n      matchLabels: {team: payments}\n"))
+		assert.EqualError(t, err, "ValidatingAdmissionPolicyBinding replica-minimum-binding.example.com: namespaceSelector is not supported")
+	})
+
+	t.Run("binding with an unknown objectSelector operator", func(t *testing.T) {
+		_, err := MergeIntoPoliciesYaml([]byte(policy + "  matchResources:\n    objectSelector:\n      matchExpressions: [{key: tier, operator: Gt}]\n"))
+		assert.EqualError(t, err, "ValidatingAdmissionPolicyBinding replica-minimum-binding.example.com: objectSelector operator Gt is not supported")
+	})
+}
+
+func TestGetKindPattern(t *testing.T) {
+	assert.Equal(t, "", getKindPattern([]string{"deployments", "*"}))
+	assert.Equal(t, "^$", getKindPattern([]string{"pods/status"}))
+	assert.Equal(t, "^(?i)(ingresses|ingress|ingresse|networkpolicies|networkpolicy)$", getKindPattern([]string{"ingresses", "networkpolicies"}))
+}
+
+func TestGetApiVersionPattern(t *testing.T) {
+	assert.Equal(t, "", getApiVersionPattern([]string{"*"}, []string{"*"}))
+	assert.Equal(t, "^((v1))$", getApiVersionPattern([]string{""}, []string{"v1"}))
+	assert.Equal(t, "^(apps/[^/]+|batch/[^/]+)$", getApiVersionPattern([]string{"apps", "batch"}, nil))
+	assert.Equal(t, "^(([^/]+/)?(v1|v1beta1))$", getApiVersionPattern([]string{"*"}, []string{"v1", "v1beta1"}))
+}
+
+func mergeAndUnmarshal(t *testing.T, content string) *defaultPolicies.EvaluationPrerunPolicies {
+	mergedContent, err := MergeIntoPoliciesYaml([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var policies *defaultPolicies.EvaluationPrerunPolicies
+	err = json.Unmarshal(mergedContent, &policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policies
+}
+
+func getRuleSchemaJson(t *testing.T, customRule *defaultPolicies.CustomRule) string {
+	return toJson(t, customRule.Schema)
+}
+
+func toJson(t *testing.T, value interface{}) string {
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(valueJson)
+}
@@ -0,0 +1,202 @@
+package assertions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	OpExists      = "exists"
+	OpNotExists   = "notExists"
+	OpEq          = "eq"
+	OpIn          = "in"
+	OpMatches     = "matches"
+	OpGte         = "gte"
+	OpLte         = "lte"
+	OpQuantityGte = "quantityGte"
+	OpQuantityLte = "quantityLte"
+)
+
+type pathSegment struct {
+	key     string
+	isArray bool
+}
+
+// Compile returns the json schema of a custom rule defined by assertions, the resources must pass all of them.
+// The schema is evaluated like any other rule schema, so the failure locations point to the asserted paths
+func Compile(assertions []defaultPolicies.Assertion) (map[string]interface{}, error) {
+	allOf := []interface{}{}
+	for index, assertion := range assertions {
+		schema, err := CompileAssertion(assertion)
+		if err != nil {
+			return nil, fmt.Errorf("assertion %d: %s", index, err.Error())
+		}
+		allOf = append(allOf, schema)
+	}
+	return map[string]interface{}{"allOf": allOf}, nil
+}
+
+// CompileAssertion returns the json schema of a single assertion
+func CompileAssertion(assertion defaultPolicies.Assertion) (map[string]interface{}, error) {
+	segments, err := parsePath(assertion.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSegment := segments[len(segments)-1]
+	var schema map[string]interface{}
+	switch assertion.Op {
+	case OpExists, OpNotExists:
+		if assertion.Value != nil {
+			return nil, fmt.Errorf("op %s doesn't take a value", assertion.Op)
+		}
+		if lastSegment.isArray {
+			return nil, fmt.Errorf("op %s requires a path that ends with a key", assertion.Op)
+		}
+		segments = segments[:len(segments)-1]
+		schema = map[string]interface{}{"required": []interface{}{lastSegment.key}}
+		if assertion.Op == OpNotExists {
+			schema = map[string]interface{}{"not": schema}
+		}
+	default:
+		schema, err = getValueSchema(assertion.Op, assertion.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the path is wrapped from its end, the keys of an exists assertion are required all along the path
+	for index := len(segments) - 1; index >= 0; index-- {
+		if segments[index].isArray {
+			schema = map[string]interface{}{"items": schema}
+			continue
+		}
+		key := segments[index].key
+		schema = map[string]interface{}{"properties": map[string]interface{}{key: schema}}
+		if assertion.Op == OpExists {
+			schema["required"] = []interface{}{key}
+		}
+	}
+
+	if len(assertion.Kinds) > 0 {
+		kinds := []interface{}{}
+		for _, kind := range assertion.Kinds {
+			kinds = append(kinds, kind)
+		}
+		schema = map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"kind": map[string]interface{}{"enum": kinds}},
+				"required":   []interface{}{"kind"},
+			},
+			"then": schema,
+		}
+	}
+	return schema, nil
+}
+
+// getValueSchema returns the schema of the value at the end of the path, a value that doesn't exist isn't checked
+func getValueSchema(op string, value interface{}) (map[string]interface{}, error) {
+	switch op {
+	case OpEq:
+		if value == nil {
+			return nil, fmt.Errorf("op %s requires a value", op)
+		}
+		return map[string]interface{}{"const": value}, nil
+	case OpIn:
+		values, ok := value.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("op %s requires a non-empty list value", op)
+		}
+		return map[string]interface{}{"enum": values}, nil
+	case OpMatches:
+		pattern, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("op %s requires a string value", op)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("op %s: invalid regular expression %q", op, pattern)
+		}
+		return map[string]interface{}{"type": "string", "pattern": pattern}, nil
+	case OpGte, OpLte:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("op %s requires a number value", op)
+		}
+		if op == OpGte {
+			return map[string]interface{}{"minimum": number}, nil
+		}
+		return map[string]interface{}{"maximum": number}, nil
+	case OpQuantityGte, OpQuantityLte:
+		quantityString, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("op %s requires a quantity string value, for example \"500m\" or \"1Gi\"", op)
+		}
+		quantity, err := resource.ParseQuantity(quantityString)
+		if err != nil {
+			return nil, fmt.Errorf("op %s: invalid quantity %q", op, quantityString)
+		}
+		// quantities are written as strings or as plain numbers, like cpu: 1
+		quantityKeyword, numberKeyword := "resourceMinimum", "minimum"
+		if op == OpQuantityLte {
+			quantityKeyword, numberKeyword = "resourceMaximum", "maximum"
+		}
+		return map[string]interface{}{
+			"if":   map[string]interface{}{"type": "string"},
+			"then": map[string]interface{}{quantityKeyword: quantityString},
+			"else": map[string]interface{}{numberKeyword: quantity.AsApproximateFloat64()},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q, valid ops are %s", op, strings.Join([]string{OpExists, OpNotExists, OpEq, OpIn, OpMatches, OpGte, OpLte, OpQuantityGte, OpQuantityLte}, ", "))
+	}
+}
+
+// parsePath splits a path like spec.containers[*].image or metadata.labels["app.kubernetes.io/name"] to its segments
+func parsePath(path string) ([]pathSegment, error) {
+	segments := []pathSegment{}
+	remaining := path
+	for remaining != "" {
+		switch {
+		case strings.HasPrefix(remaining, "[*]"):
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("invalid path %q: path must start with a key", path)
+			}
+			segments = append(segments, pathSegment{isArray: true})
+			remaining = remaining[len("[*]"):]
+		case strings.HasPrefix(remaining, "[\"") || strings.HasPrefix(remaining, "['"):
+			closing := string(remaining[1]) + "]"
+			end := strings.Index(remaining[2:], closing)
+			if end <= 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed or empty quoted key", path)
+			}
+			segments = append(segments, pathSegment{key: remaining[2 : 2+end]})
+			remaining = remaining[2+end+len(closing):]
+		case strings.HasPrefix(remaining, "["):
+			return nil, fmt.Errorf("invalid path %q: only [*] and quoted keys are supported in brackets", path)
+		default:
+			if len(segments) > 0 {
+				if remaining[0] != '.' {
+					return nil, fmt.Errorf("invalid path %q: expected . before %q", path, remaining)
+				}
+				remaining = remaining[1:]
+			}
+			end := strings.IndexAny(remaining, ".[")
+			if end == -1 {
+				end = len(remaining)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: remaining[:end]})
+			remaining = remaining[end:]
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path is empty")
+	}
+	return segments, nil
+}
@@ -0,0 +1,125 @@
+package assertions
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+const deploymentYaml = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: rss-site
+  labels:
+    app.kubernetes.io/name: rss-site
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: front-end
+          image: registry.corp/nginx
+          resources:
+            limits:
+              memory: 1Gi
+              cpu: 2
+        - name: rss-reader
+          image: docker.io/rss-php-nginx:v1
+`
+
+func TestParsePath(t *testing.T) {
+	segments, err := parsePath(`spec.containers[*].image`)
+	assert.Nil(t, err)
+	assert.Equal(t, []pathSegment{{key: "spec"}, {key: "containers"}, {isArray: true}, {key: "image"}}, segments)
+
+	segments, err = parsePath(`metadata.labels["app.kubernetes.io/name"]`)
+	assert.Nil(t, err)
+	assert.Equal(t, []pathSegment{{key: "metadata"}, {key: "labels"}, {key: "app.kubernetes.io/name"}}, segments)
+
+	_, err = parsePath(`spec..replicas`)
+	assert.Equal(t, errors.New(`invalid path "spec..replicas": empty key`), err)
+
+	_, err = parsePath(`spec.containers[0].image`)
+	assert.Equal(t, errors.New(`invalid path "spec.containers[0].image": only [*] and quoted keys are supported in brackets`), err)
+
+	_, err = parsePath(`[*].image`)
+	assert.Equal(t, errors.New(`invalid path "[*].image": path must start with a key`), err)
+}
+
+func TestCompileAssertion(t *testing.T) {
+	schema, err := CompileAssertion(defaultPolicies.Assertion{Kinds: []string{"Deployment"}, Path: "spec.replicas", Op: OpExists})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"if": map[string]interface{}{
+			"properties": map[string]interface{}{"kind": map[string]interface{}{"enum": []interface{}{"Deployment"}}},
+			"required":   []interface{}{"kind"},
+		},
+		"then": map[string]interface{}{
+			"properties": map[string]interface{}{"spec": map[string]interface{}{"required": []interface{}{"replicas"}}},
+			"required":   []interface{}{"spec"},
+		},
+	}, schema)
+
+	_, err = CompileAssertion(defaultPolicies.Assertion{Path: "spec.replicas", Op: OpExists, Value: true})
+	assert.Equal(t, errors.New("op exists doesn't take a value"), err)
+
+	_, err = CompileAssertion(defaultPolicies.Assertion{Path: "spec.containers[*]", Op: OpNotExists})
+	assert.Equal(t, errors.New("op notExists requires a path that ends with a key"), err)
+
+	_, err = CompileAssertion(defaultPolicies.Assertion{Path: "spec.replicas", Op: OpGte, Value: "2"})
+	assert.Equal(t, errors.New("op gte requires a number value"), err)
+
+	_, err = CompileAssertion(defaultPolicies.Assertion{Path: "spec.replicas", Op: OpIn, Value: []interface{}{}})
+	assert.Equal(t, errors.New("op in requires a non-empty list value"), err)
+
+	_, err = CompileAssertion(defaultPolicies.Assertion{Path: "metadata.name", Op: OpMatches, Value: "(rss"})
+	assert.Equal(t, errors.New(`op matches: invalid regular expression "(rss"`), err)
+}
+
+func TestCompiledAssertionsValidation(t *testing.T) {
+	tests := []struct {
+		name                      string
+		assertion                 defaultPolicies.Assertion
+		expectedInstanceLocations []string
+	}{
+		{"matches", defaultPolicies.Assertion{Kinds: []string{"Deployment"}, Path: "spec.template.spec.containers[*].image", Op: OpMatches, Value: "^registry.corp/"}, []string{"/spec/template/spec/containers/1/image"}},
+		{"matches of another kind", defaultPolicies.Assertion{Kinds: []string{"StatefulSet"}, Path: "spec.template.spec.containers[*].image", Op: OpMatches, Value: "^registry.corp/"}, nil},
+		{"exists", defaultPolicies.Assertion{Path: "spec.template.spec.containers[*].resources.limits", Op: OpExists}, []string{"/spec/template/spec/containers/1"}},
+		{"notExists", defaultPolicies.Assertion{Path: "spec.template.spec.containers[*].resources", Op: OpNotExists}, []string{"/spec/template/spec/containers/0"}},
+		{"eq", defaultPolicies.Assertion{Path: `metadata.labels["app.kubernetes.io/name"]`, Op: OpEq, Value: "rss"}, []string{"/metadata/labels/app.kubernetes.io~1name"}},
+		{"in", defaultPolicies.Assertion{Path: "spec.template.spec.containers[*].name", Op: OpIn, Value: []interface{}{"front-end", "rss-reader"}}, nil},
+		{"gte", defaultPolicies.Assertion{Path: "spec.replicas", Op: OpGte, Value: float64(3)}, []string{"/spec/replicas"}},
+		{"lte", defaultPolicies.Assertion{Path: "spec.replicas", Op: OpLte, Value: float64(3)}, nil},
+		{"quantityLte of a string", defaultPolicies.Assertion{Path: "spec.template.spec.containers[*].resources.limits.memory", Op: OpQuantityLte, Value: "512Mi"}, []string{"/spec/template/spec/containers/0/resources/limits/memory"}},
+		{"quantityGte of a number", defaultPolicies.Assertion{Path: "spec.template.spec.containers[*].resources.limits.cpu", Op: OpQuantityGte, Value: "2500m"}, []string{"/spec/template/spec/containers/0/resources/limits/cpu"}},
+		{"quantityLte of a number", defaultPolicies.Assertion{Path: "spec.template.spec.containers[*].resources.limits.cpu", Op: OpQuantityLte, Value: "2"}, nil},
+	}
+
+	deploymentJson, err := yaml.YAMLToJSON([]byte(deploymentYaml))
+	assert.Nil(t, err)
+	validator := jsonSchemaValidator.New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Compile([]defaultPolicies.Assertion{tt.assertion})
+			assert.Nil(t, err)
+			schemaJson, err := json.Marshal(schema)
+			assert.Nil(t, err)
+
+			validationErrors, err := validator.Validate(string(schemaJson), deploymentJson)
+			assert.Nil(t, err)
+
+			var instanceLocations []string
+			for _, validationError := range validationErrors {
+				instanceLocations = append(instanceLocations, validationError.InstanceLocation)
+			}
+			assert.Equal(t, tt.expectedInstanceLocations, instanceLocations)
+		})
+	}
+}
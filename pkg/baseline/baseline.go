@@ -0,0 +1,186 @@
+// This package records the violations of a run in a baseline file, so later runs fail only on new violations
+
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/cliClient"
+)
+
+const baselineApiVersion = "v1"
+
+type Baseline struct {
+	ApiVersion string  `json:"apiVersion"`
+	Entries    []Entry `json:"entries"`
+}
+
+// Entry is a single violation, identified by its fingerprint.
+// The fingerprint doesn't include the line of the violation, so moving a resource in its file doesn't change it
+type Entry struct {
+	Fingerprint    string `yaml:"fingerprint" json:"fingerprint" xml:"fingerprint"`
+	File           string `yaml:"file" json:"file" xml:"file"`
+	Kind           string `yaml:"kind" json:"kind" xml:"kind"`
+	Name           string `yaml:"name" json:"name" xml:"name"`
+	Namespace      string `yaml:"namespace" json:"namespace" xml:"namespace"`
+	RuleIdentifier string `yaml:"ruleIdentifier" json:"ruleIdentifier" xml:"ruleIdentifier"`
+	SchemaPath     string `yaml:"schemaPath" json:"schemaPath" xml:"schemaPath"`
+}
+
+func newEntry(fileName string, ruleIdentifier string, configuration cliClient.Configuration, schemaPath string) Entry {
+	entry := Entry{
+		File:           getRelativePath(fileName),
+		Kind:           configuration.Kind,
+		Name:           configuration.Name,
+		Namespace:      configuration.Namespace,
+		RuleIdentifier: ruleIdentifier,
+		SchemaPath:     schemaPath,
+	}
+	fingerprintFields := []string{entry.File, entry.Kind, entry.Name, entry.Namespace, entry.RuleIdentifier, entry.SchemaPath}
+	entry.Fingerprint = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(fingerprintFields, "\n"))))
+	return entry
+}
+
+// getRelativePath keeps the baseline valid when the repository is checked out in another directory
+func getRelativePath(fileName string) string {
+	if filepath.IsAbs(fileName) {
+		if workingDirectory, err := os.Getwd(); err == nil {
+			if relativePath, err := filepath.Rel(workingDirectory, fileName); err == nil {
+				fileName = relativePath
+			}
+		}
+	}
+	return filepath.ToSlash(fileName)
+}
+
+// New returns a baseline of the failures in the results, skipped occurrences and warnings don't fail the run so they are not recorded
+func New(failedRulesByFiles map[string]map[string]*cliClient.FailedRule) *Baseline {
+	baseline := &Baseline{ApiVersion: baselineApiVersion, Entries: []Entry{}}
+	for fileName, failedRules := range failedRulesByFiles {
+		for ruleIdentifier, failedRule := range failedRules {
+			if failedRule.IsWarning {
+				continue
+			}
+			for _, configuration := range failedRule.Configurations {
+				if configuration.IsSkipped {
+					continue
+				}
+				for _, failureLocation := range configuration.FailureLocations {
+					baseline.Entries = append(baseline.Entries, newEntry(fileName, ruleIdentifier, configuration, failureLocation.SchemaPath))
+				}
+			}
+		}
+	}
+
+	sortEntries(baseline.Entries)
+	return baseline
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].File != entries[j].File {
+			return entries[i].File < entries[j].File
+		}
+		return entries[i].Fingerprint < entries[j].Fingerprint
+	})
+}
+
+func Load(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file %s: %s", path, err.Error())
+	}
+
+	var baseline Baseline
+	err = json.Unmarshal(content, &baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file %s: %s", path, err.Error())
+	}
+	if baseline.ApiVersion != baselineApiVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q in baseline file %s", baseline.ApiVersion, path)
+	}
+	return &baseline, nil
+}
+
+func (baseline *Baseline) Write(path string) error {
+	content, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Result is the outcome of applying a baseline on the results of a run
+type Result struct {
+	// HiddenCount is the number of violations that are in the baseline
+	HiddenCount int `yaml:"hiddenCount" json:"hiddenCount" xml:"hiddenCount"`
+	// FixedEntries are the baseline entries without a matching violation, they can be pruned from the baseline
+	FixedEntries []Entry `yaml:"fixedEntries" json:"fixedEntries" xml:"fixedEntries"`
+}
+
+// Apply returns the results without the violations that are in the baseline.
+// An occurrence is removed when all of its failure locations are in the baseline, otherwise only the known locations are removed
+func (baseline *Baseline) Apply(failedRulesByFiles map[string]map[string]*cliClient.FailedRule) (map[string]map[string]*cliClient.FailedRule, Result) {
+	unmatchedEntries := make(map[string]Entry, len(baseline.Entries))
+	knownFingerprints := make(map[string]bool, len(baseline.Entries))
+	for _, entry := range baseline.Entries {
+		unmatchedEntries[entry.Fingerprint] = entry
+		knownFingerprints[entry.Fingerprint] = true
+	}
+
+	result := Result{FixedEntries: []Entry{}}
+	newFailedRulesByFiles := make(map[string]map[string]*cliClient.FailedRule)
+	for fileName, failedRules := range failedRulesByFiles {
+		for ruleIdentifier, failedRule := range failedRules {
+			var configurations []cliClient.Configuration
+			for _, configuration := range failedRule.Configurations {
+				if configuration.IsSkipped || failedRule.IsWarning {
+					configurations = append(configurations, configuration)
+					continue
+				}
+
+				var newFailureLocations []cliClient.FailureLocation
+				for _, failureLocation := range configuration.FailureLocations {
+					entry := newEntry(fileName, ruleIdentifier, configuration, failureLocation.SchemaPath)
+					if knownFingerprints[entry.Fingerprint] {
+						delete(unmatchedEntries, entry.Fingerprint)
+						result.HiddenCount++
+					} else {
+						newFailureLocations = append(newFailureLocations, failureLocation)
+					}
+				}
+
+				if len(newFailureLocations) == len(configuration.FailureLocations) {
+					configurations = append(configurations, configuration)
+				} else if len(newFailureLocations) > 0 {
+					configuration.FailureLocations = newFailureLocations
+					configuration.Occurrences = len(newFailureLocations)
+					configurations = append(configurations, configuration)
+				}
+			}
+
+			if len(configurations) == 0 {
+				continue
+			}
+			newFailedRule := *failedRule
+			newFailedRule.Configurations = configurations
+			if _, ok := newFailedRulesByFiles[fileName]; !ok {
+				newFailedRulesByFiles[fileName] = make(map[string]*cliClient.FailedRule)
+			}
+			newFailedRulesByFiles[fileName][ruleIdentifier] = &newFailedRule
+		}
+	}
+
+	for _, entry := range unmatchedEntries {
+		result.FixedEntries = append(result.FixedEntries, entry)
+	}
+	sortEntries(result.FixedEntries)
+
+	return newFailedRulesByFiles, result
+}
@@ -0,0 +1,119 @@
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFailedRulesByFiles() map[string]map[string]*cliClient.FailedRule {
+	return map[string]map[string]*cliClient.FailedRule{
+		"manifests/web.yaml": {
+			"CONTAINERS_MISSING_MEMORY_LIMIT_KEY": {
+				Name: "Ensure each container has a configured memory limit",
+				Configurations: []cliClient.Configuration{{
+					Name:        "web",
+					Namespace:   "prod",
+					Kind:        "Deployment",
+					Occurrences: 2,
+					FailureLocations: []cliClient.FailureLocation{
+						{SchemaPath: "/spec/template/spec/containers/0/resources/limits", FailedErrorLine: 20},
+						{SchemaPath: "/spec/template/spec/containers/1/resources/limits", FailedErrorLine: 30},
+					},
+				}},
+			},
+			"WORKLOAD_MISSING_LABEL_OWNER_VALUE": {
+				Name: "Ensure workload has a configured `owner` label",
+				Configurations: []cliClient.Configuration{{
+					Name:             "web",
+					Namespace:        "prod",
+					Kind:             "Deployment",
+					Occurrences:      1,
+					FailureLocations: []cliClient.FailureLocation{{SchemaPath: "/metadata/labels", FailedErrorLine: 5}},
+				}},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	baseline := New(newFailedRulesByFiles())
+
+	assert.Equal(t, "v1", baseline.ApiVersion)
+	assert.Equal(t, 3, len(baseline.Entries))
+	for _, entry := range baseline.Entries {
+		assert.Equal(t, "manifests/web.yaml", entry.File)
+		assert.Equal(t, "prod", entry.Namespace)
+		assert.Equal(t, 64, len(entry.Fingerprint))
+	}
+}
+
+func TestFingerprintIgnoresLines(t *testing.T) {
+	failedRulesByFiles := newFailedRulesByFiles()
+	baseline := New(failedRulesByFiles)
+
+	failedRulesByFiles["manifests/web.yaml"]["WORKLOAD_MISSING_LABEL_OWNER_VALUE"].Configurations[0].FailureLocations[0].FailedErrorLine = 50
+	assert.Equal(t, baseline, New(failedRulesByFiles))
+}
+
+func TestApply(t *testing.T) {
+	baseline := New(newFailedRulesByFiles())
+
+	t.Run("hides all the known violations", func(t *testing.T) {
+		results, result := baseline.Apply(newFailedRulesByFiles())
+		assert.Empty(t, results)
+		assert.Equal(t, 3, result.HiddenCount)
+		assert.Empty(t, result.FixedEntries)
+	})
+
+	t.Run("reports new violations and fixed entries", func(t *testing.T) {
+		failedRulesByFiles := newFailedRulesByFiles()
+		// the second container was fixed and a third container was added
+		memoryLimitConfiguration := &failedRulesByFiles["manifests/web.yaml"]["CONTAINERS_MISSING_MEMORY_LIMIT_KEY"].Configurations[0]
+		memoryLimitConfiguration.FailureLocations[1].SchemaPath = "/spec/template/spec/containers/2/resources/limits"
+		// a new resource in the same file
+		failedRulesByFiles["manifests/web.yaml"]["WORKLOAD_MISSING_LABEL_OWNER_VALUE"].Configurations = append(
+			failedRulesByFiles["manifests/web.yaml"]["WORKLOAD_MISSING_LABEL_OWNER_VALUE"].Configurations,
+			cliClient.Configuration{Name: "worker", Namespace: "prod", Kind: "Deployment", Occurrences: 1, FailureLocations: []cliClient.FailureLocation{{SchemaPath: "/metadata/labels"}}},
+		)
+
+		results, result := baseline.Apply(failedRulesByFiles)
+		assert.Equal(t, 2, result.HiddenCount)
+		assert.Equal(t, 1, len(result.FixedEntries))
+		assert.Equal(t, "/spec/template/spec/containers/1/resources/limits", result.FixedEntries[0].SchemaPath)
+
+		memoryLimitConfigurations := results["manifests/web.yaml"]["CONTAINERS_MISSING_MEMORY_LIMIT_KEY"].Configurations
+		assert.Equal(t, 1, len(memoryLimitConfigurations))
+		assert.Equal(t, 1, memoryLimitConfigurations[0].Occurrences)
+		assert.Equal(t, "/spec/template/spec/containers/2/resources/limits", memoryLimitConfigurations[0].FailureLocations[0].SchemaPath)
+
+		ownerLabelConfigurations := results["manifests/web.yaml"]["WORKLOAD_MISSING_LABEL_OWNER_VALUE"].Configurations
+		assert.Equal(t, 1, len(ownerLabelConfigurations))
+		assert.Equal(t, "worker", ownerLabelConfigurations[0].Name)
+
+		// the results of the run are not modified
+		assert.Equal(t, 2, len(failedRulesByFiles["manifests/web.yaml"]["CONTAINERS_MISSING_MEMORY_LIMIT_KEY"].Configurations[0].FailureLocations))
+	})
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	baseline := New(newFailedRulesByFiles())
+	path := filepath.Join(t.TempDir(), ".datree-baseline.json")
+
+	err := baseline.Write(path)
+	assert.Nil(t, err)
+
+	loadedBaseline, err := Load(path)
+	assert.Nil(t, err)
+	assert.Equal(t, baseline, loadedBaseline)
+}
+
+func TestLoadInvalidBaseline(t *testing.T) {
+	_, err := Load("./test_fixtures/missing.json")
+	assert.EqualError(t, err, "failed to read baseline file ./test_fixtures/missing.json: open ./test_fixtures/missing.json: no such file or directory")
+
+	_, err = Load("./test_fixtures/wrongApiVersion.json")
+	assert.EqualError(t, err, "unsupported apiVersion \"v2\" in baseline file ./test_fixtures/wrongApiVersion.json")
+}
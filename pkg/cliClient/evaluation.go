@@ -8,6 +8,7 @@ import (
 
 	"github.com/datreeio/datree/pkg/ciContext"
 	"github.com/datreeio/datree/pkg/defaultPolicies"
+	"github.com/datreeio/datree/pkg/exceptions"
 	"github.com/datreeio/datree/pkg/extractor"
 )
 
@@ -141,12 +142,19 @@ type FailureLocation struct {
 
 type Configuration struct {
 	Name                      string            `json:"metadataName"`
+	Namespace                 string            `json:"namespace,omitempty"`
 	Kind                      string            `json:"kind"`
 	Occurrences               int               `json:"occurrences"`
 	IsSkipped                 bool              `json:"isSkipped"`
 	SkipMessage               string            `json:"skipMessage"`
 	FailureLocations          []FailureLocation `json:"failureLocation"`
 	ValidationFailureMessages []string          `json:"validationFailureMessages"`
+	// Exception is set when the occurrences are skipped by an exception of the exceptions file
+	Exception *exceptions.Exception `json:"exception,omitempty"`
+	// DocumentIndex is set when the configuration was rendered from a source, it's the index of the configuration among the configurations of the source
+	DocumentIndex *int `json:"documentIndex,omitempty"`
+	// RenderedFrom is the helm chart or kustomization directory that the configuration was rendered from
+	RenderedFrom string `json:"renderedFrom,omitempty"`
 }
 
 type FailedRule struct {
@@ -154,6 +162,8 @@ type FailedRule struct {
 	DocumentationUrl string          `json:"DocumentationUrl"`
 	MessageOnFailure string          `json:"messageOnFailure"`
 	Configurations   []Configuration `json:"configurations"`
+	IsWarning        bool            `json:"isWarning,omitempty"`
+	Severity         string          `json:"severity,omitempty"`
 }
 
 type EvaluationResultRequest struct {
@@ -18,17 +18,60 @@ type CustomRule struct {
 	DefaultMessageOnFailure string      `json:"defaultMessageOnFailure"`
 	Schema                  interface{} `json:"schema"`
 	JsonSchema              string      `json:"jsonSchema"`
+	Assertions              []Assertion `json:"assertions,omitempty"`
+	Severity                string      `json:"severity,omitempty"`
+}
+
+// Assertion is a check of the value at the path of the resources of the given kinds, it's the short form of a custom rule schema
+type Assertion struct {
+	Kinds []string    `json:"kinds,omitempty"`
+	Path  string      `json:"path"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value,omitempty"`
 }
 
 type Rule struct {
-	Identifier       string `json:"identifier"`
-	MessageOnFailure string `json:"messageOnFailure"`
+	Identifier       string        `json:"identifier"`
+	MessageOnFailure string        `json:"messageOnFailure"`
+	Severity         string        `json:"severity,omitempty"`
+	Match            *RuleSelector `json:"match,omitempty"`
+	Exclude          *RuleSelector `json:"exclude,omitempty"`
+	// Params set the parameters of a parameterized default rule
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// RuleSelector selects resources by kind, namespace, labels, annotations and the path of their file.
+// A resource is selected when it matches every field that is set, and any of the values of a list field
+type RuleSelector struct {
+	Kinds       []string       `json:"kinds,omitempty"`
+	Namespaces  []string       `json:"namespaces,omitempty"`
+	Labels      *LabelSelector `json:"labels,omitempty"`
+	Annotations *LabelSelector `json:"annotations,omitempty"`
+	Files       []string       `json:"files,omitempty"`
+}
+
+// LabelSelector is a kubernetes label selector, it's used for annotations as well
+type LabelSelector struct {
+	MatchLabels      map[string]string          `json:"matchLabels,omitempty"`
+	MatchExpressions []LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+type LabelSelectorRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
 }
 
 type Policy struct {
 	Name      string `json:"name"`
 	IsDefault bool   `json:"isDefault,omitempty"`
-	Rules     []Rule `json:"rules"`
+	// Extends are the names of the policies whose rules the policy inherits, the rules of the policy override the inherited rules
+	Extends []string `json:"extends,omitempty"`
+	// Disable are the identifiers of inherited rules that the policy doesn't run
+	Disable []string `json:"disable,omitempty"`
+	// MessageOverrides replace the messageOnFailure of rules by their identifier
+	MessageOverrides map[string]string `json:"messageOverrides,omitempty"`
+	Rules            []Rule            `json:"rules"`
 }
 
 //go:embed defaultPolicies.yaml
@@ -22,19 +22,65 @@ type DefaultRuleDefinition struct {
 	Name             string                 `yaml:"name"`
 	UniqueName       string                 `yaml:"uniqueName"`
 	EnabledByDefault bool                   `yaml:"enabledByDefault"`
+	Severity         string                 `yaml:"severity"`
 	DocumentationUrl string                 `yaml:"documentationUrl"`
 	MessageOnFailure string                 `yaml:"messageOnFailure"`
 	Categories       []string               `yaml:"categories"`
 	Schema           map[string]interface{} `yaml:"schema"`
+	// Parameters are the values of the schema that policies can set, the messageOnFailure can interpolate them
+	Parameters []*RuleParameter `yaml:"parameters"`
 }
 
 func GetDefaultRules() (*DefaultRulesDefinitions, error) {
+	embeddedDefaultRules, err := YAMLToStruct(embeddedDefaultRulesYamlContent)
+	if err != nil {
+		return nil, err
+	}
+
 	configDefaultRulesYamlContent, err := getDefaultRulesFromFile()
-	if err == nil {
-		return YAMLToStruct(configDefaultRulesYamlContent)
+	if err != nil {
+		return embeddedDefaultRules, nil
+	}
+
+	defaultRules, err := YAMLToStruct(configDefaultRulesYamlContent)
+	if err != nil {
+		return nil, err
 	}
+	defaultRules.setEmbeddedDefinitions(embeddedDefaultRules)
+	return defaultRules, nil
+}
+
+// setEmbeddedDefinitions sets the fields that the rules file saved from the backend lacks from the rules embedded in the CLI
+func (defaultRulesDefinitions *DefaultRulesDefinitions) setEmbeddedDefinitions(embeddedDefaultRules *DefaultRulesDefinitions) {
+	embeddedRulesByUniqueName := make(map[string]*DefaultRuleDefinition)
+	for _, embeddedRule := range embeddedDefaultRules.Rules {
+		embeddedRulesByUniqueName[embeddedRule.UniqueName] = embeddedRule
+	}
+
+	for _, rule := range defaultRulesDefinitions.Rules {
+		embeddedRule, ok := embeddedRulesByUniqueName[rule.UniqueName]
+		if !ok {
+			continue
+		}
+		if rule.Severity == "" {
+			rule.Severity = embeddedRule.Severity
+		}
+		if len(rule.Parameters) == 0 && len(embeddedRule.Parameters) > 0 {
+			rule.setEmbeddedParameters(embeddedRule.Parameters)
+		}
+	}
+}
 
-	return YAMLToStruct(embeddedDefaultRulesYamlContent)
+// setEmbeddedParameters sets the parameters of the rule, unless the schema of the rule doesn't have the values that the parameters set
+func (rule *DefaultRuleDefinition) setEmbeddedParameters(parameters []*RuleParameter) {
+	rule.Parameters = parameters
+	defaultValues, err := rule.ResolveParameters(nil)
+	if err == nil {
+		_, err = rule.GetSchemaWithParameters(defaultValues)
+	}
+	if err != nil {
+		rule.Parameters = nil
+	}
 }
 
 func YAMLToStruct(content string) (*DefaultRulesDefinitions, error) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/datreeio/datree/pkg/fileReader"
@@ -81,6 +82,47 @@ func TestDefaultRulesHasUniqueDocumentationUrlsInRules(t *testing.T) {
 	assert.Nil(t, uniquenessValidationError)
 }
 
+func TestGetDefaultRulesFromBackendRulesFile(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	backendDefaultRules, err := os.ReadFile("./test_fixtures/backendDefaultRules.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Mkdir(filepath.Join(homeDir, ".datree"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(homeDir, ".datree", "defaultRules.yaml"), backendDefaultRules, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defaultRules, err := GetDefaultRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 2, len(defaultRules.Rules))
+	// the severity of a rule the CLI embeds is taken from the embedded rule, the policy defaults the severity of the other rules
+	assert.Equal(t, "medium", defaultRules.Rules[0].Severity)
+	assert.Equal(t, "", defaultRules.Rules[1].Severity)
+
+	// the parameters of a rule the CLI embeds are taken from the embedded rule, so its templates are filled with the defaults
+	replicasRule := defaultRules.Rules[0]
+	assert.Equal(t, "minReplicas", replicasRule.Parameters[0].Name)
+	parametersValues, err := replicasRule.ResolveParameters(map[string]interface{}{"minReplicas": float64(3)})
+	assert.NoError(t, err)
+	schema, err := replicasRule.GetSchemaWithParameters(parametersValues)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), schema["then"].(map[string]interface{})["properties"].(map[string]interface{})["spec"].(map[string]interface{})["properties"].(map[string]interface{})["replicas"].(map[string]interface{})["minimum"])
+
+	defaultValues, err := replicasRule.ResolveParameters(nil)
+	assert.NoError(t, err)
+	message, err := replicasRule.InterpolateMessage("running {{ .minReplicas }} or more replicas", defaultValues)
+	assert.NoError(t, err)
+	assert.Equal(t, "running 2 or more replicas", message)
+	assert.Empty(t, defaultRules.Rules[1].Parameters)
+}
+
 func getFileFromPath(path string) (string, error) {
 	fileReader := fileReader.CreateFileReader(nil)
 	fileContent, readFileError := fileReader.ReadFileContent(path)
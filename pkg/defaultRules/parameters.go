@@ -0,0 +1,170 @@
+package defaultRules
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+	ParameterTypeArray   = "array"
+)
+
+// RuleParameter is a value of the rule schema that a policy can set with the params of the policy rule.
+// SchemaPaths are json pointers to the values of the schema that the parameter sets, the schema holds the default value
+type RuleParameter struct {
+	Name        string      `yaml:"name"`
+	Type        string      `yaml:"type"`
+	Default     interface{} `yaml:"default"`
+	Description string      `yaml:"description"`
+	SchemaPaths []string    `yaml:"schemaPaths"`
+}
+
+// ValidateValue returns an error when the value doesn't fit the type of the parameter
+func (parameter *RuleParameter) ValidateValue(value interface{}) error {
+	isValid := false
+	switch parameter.Type {
+	case ParameterTypeString:
+		_, isValid = value.(string)
+	case ParameterTypeInteger:
+		number, isNumber := value.(float64)
+		isValid = isNumber && number == math.Trunc(number)
+	case ParameterTypeNumber:
+		_, isValid = value.(float64)
+	case ParameterTypeBoolean:
+		_, isValid = value.(bool)
+	case ParameterTypeArray:
+		_, isValid = value.([]interface{})
+	}
+
+	if !isValid {
+		return fmt.Errorf("parameter %s must be of type %s, got %v", parameter.Name, parameter.Type, value)
+	}
+	return nil
+}
+
+// GetParameter returns the parameter of the rule with the given name, or nil when the rule has no such parameter
+func (rule *DefaultRuleDefinition) GetParameter(name string) *RuleParameter {
+	for _, parameter := range rule.Parameters {
+		if parameter.Name == name {
+			return parameter
+		}
+	}
+	return nil
+}
+
+// ResolveParameters returns the values of all the parameters of the rule, params override the defaults
+func (rule *DefaultRuleDefinition) ResolveParameters(params map[string]interface{}) (map[string]interface{}, error) {
+	for name, value := range params {
+		parameter := rule.GetParameter(name)
+		if parameter == nil {
+			return nil, fmt.Errorf("rule %s has no parameter %s", rule.UniqueName, name)
+		}
+		if err := parameter.ValidateValue(value); err != nil {
+			return nil, fmt.Errorf("rule %s: %s", rule.UniqueName, err.Error())
+		}
+	}
+
+	values := make(map[string]interface{}, len(rule.Parameters))
+	for _, parameter := range rule.Parameters {
+		values[parameter.Name] = parameter.Default
+		if value, ok := params[parameter.Name]; ok {
+			values[parameter.Name] = value
+		}
+	}
+	return values, nil
+}
+
+// GetSchemaWithParameters returns a copy of the rule schema with the values of the parameters set in it
+func (rule *DefaultRuleDefinition) GetSchemaWithParameters(values map[string]interface{}) (map[string]interface{}, error) {
+	if len(rule.Parameters) == 0 {
+		return rule.Schema, nil
+	}
+
+	// the schema is copied since the rule definitions are shared between the policies of a run
+	schemaJson, err := json.Marshal(rule.Schema)
+	if err != nil {
+		return nil, err
+	}
+	var schema map[string]interface{}
+	err = json.Unmarshal(schemaJson, &schema)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parameter := range rule.Parameters {
+		for _, schemaPath := range parameter.SchemaPaths {
+			err = setSchemaValue(schema, schemaPath, values[parameter.Name])
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: parameter %s: %s", rule.UniqueName, parameter.Name, err.Error())
+			}
+		}
+	}
+	return schema, nil
+}
+
+// InterpolateMessage returns the message with the {{ .parameterName }} templates replaced by the values of the parameters,
+// the messages of rules without parameters are returned as is
+func (rule *DefaultRuleDefinition) InterpolateMessage(message string, values map[string]interface{}) (string, error) {
+	if len(rule.Parameters) == 0 || !strings.Contains(message, "{{") {
+		return message, nil
+	}
+
+	messageTemplate, err := template.New("message").Option("missingkey=error").Parse(message)
+	if err != nil {
+		return "", fmt.Errorf("invalid message template %q: %s", message, err.Error())
+	}
+
+	var sb strings.Builder
+	err = messageTemplate.Execute(&sb, values)
+	if err != nil {
+		return "", fmt.Errorf("invalid message template %q: %s", message, err.Error())
+	}
+	return sb.String(), nil
+}
+
+// setSchemaValue replaces the value that the json pointer points to, the value must already exist in the schema
+func setSchemaValue(schema map[string]interface{}, pointer string, value interface{}) error {
+	if !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf("invalid schema path %q", pointer)
+	}
+	segments := strings.Split(pointer[1:], "/")
+
+	var node interface{} = schema
+	for index, segment := range segments {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		isLast := index == len(segments)-1
+
+		switch typedNode := node.(type) {
+		case map[string]interface{}:
+			if _, ok := typedNode[segment]; !ok {
+				return fmt.Errorf("schema path %q doesn't exist", pointer)
+			}
+			if isLast {
+				typedNode[segment] = value
+				return nil
+			}
+			node = typedNode[segment]
+		case []interface{}:
+			itemIndex, err := strconv.Atoi(segment)
+			if err != nil || itemIndex < 0 || itemIndex >= len(typedNode) {
+				return fmt.Errorf("schema path %q doesn't exist", pointer)
+			}
+			if isLast {
+				typedNode[itemIndex] = value
+				return nil
+			}
+			node = typedNode[itemIndex]
+		default:
+			return fmt.Errorf("schema path %q doesn't exist", pointer)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package defaultRules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newParameterizedRule() *DefaultRuleDefinition {
+	return &DefaultRuleDefinition{
+		UniqueName:       "DEPLOYMENT_INCORRECT_REPLICAS_VALUE",
+		MessageOnFailure: "running {{ .minReplicas }} or more replicas",
+		Schema: map[string]interface{}{
+			"allOf": []interface{}{
+				map[string]interface{}{"properties": map[string]interface{}{"replicas": map[string]interface{}{"minimum": float64(2)}}},
+			},
+		},
+		Parameters: []*RuleParameter{
+			{Name: "minReplicas", Type: ParameterTypeInteger, Default: float64(2), SchemaPaths: []string{"/allOf/0/properties/replicas/minimum"}},
+		},
+	}
+}
+
+func TestRuleParameterValidateValue(t *testing.T) {
+	assert.Nil(t, (&RuleParameter{Name: "p", Type: ParameterTypeInteger}).ValidateValue(float64(3)))
+	assert.Equal(t, errors.New("parameter p must be of type integer, got 3.5"), (&RuleParameter{Name: "p", Type: ParameterTypeInteger}).ValidateValue(3.5))
+	assert.Nil(t, (&RuleParameter{Name: "p", Type: ParameterTypeNumber}).ValidateValue(3.5))
+	assert.Nil(t, (&RuleParameter{Name: "p", Type: ParameterTypeString}).ValidateValue("value"))
+	assert.NotNil(t, (&RuleParameter{Name: "p", Type: ParameterTypeString}).ValidateValue(float64(1)))
+	assert.Nil(t, (&RuleParameter{Name: "p", Type: ParameterTypeBoolean}).ValidateValue(true))
+	assert.Nil(t, (&RuleParameter{Name: "p", Type: ParameterTypeArray}).ValidateValue([]interface{}{"a"}))
+	assert.NotNil(t, (&RuleParameter{Name: "p", Type: ParameterTypeArray}).ValidateValue("a"))
+}
+
+func TestResolveParameters(t *testing.T) {
+	rule := newParameterizedRule()
+
+	values, err := rule.ResolveParameters(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"minReplicas": float64(2)}, values)
+
+	values, err = rule.ResolveParameters(map[string]interface{}{"minReplicas": float64(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"minReplicas": float64(3)}, values)
+
+	_, err = rule.ResolveParameters(map[string]interface{}{"maxReplicas": float64(3)})
+	assert.Equal(t, errors.New("rule DEPLOYMENT_INCORRECT_REPLICAS_VALUE has no parameter maxReplicas"), err)
+
+	_, err = rule.ResolveParameters(map[string]interface{}{"minReplicas": "3"})
+	assert.Equal(t, errors.New("rule DEPLOYMENT_INCORRECT_REPLICAS_VALUE: parameter minReplicas must be of type integer, got 3"), err)
+}
+
+func TestGetSchemaWithParameters(t *testing.T) {
+	rule := newParameterizedRule()
+
+	schema, err := rule.GetSchemaWithParameters(map[string]interface{}{"minReplicas": float64(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"allOf": []interface{}{
+			map[string]interface{}{"properties": map[string]interface{}{"replicas": map[string]interface{}{"minimum": float64(3)}}},
+		},
+	}, schema)
+	// the schema of the rule definition keeps the default
+	assert.Equal(t, newParameterizedRule().Schema, rule.Schema)
+
+	rule.Parameters[0].SchemaPaths = []string{"/allOf/1/properties"}
+	_, err = rule.GetSchemaWithParameters(map[string]interface{}{"minReplicas": float64(3)})
+	assert.Equal(t, errors.New("rule DEPLOYMENT_INCORRECT_REPLICAS_VALUE: parameter minReplicas: schema path \"/allOf/1/properties\" doesn't exist"), err)
+}
+
+func TestInterpolateMessage(t *testing.T) {
+	rule := newParameterizedRule()
+
+	message, err := rule.InterpolateMessage(rule.MessageOnFailure, map[string]interface{}{"minReplicas": float64(3)})
+	assert.Nil(t, err)
+	assert.Equal(t, "running 3 or more replicas", message)
+
+	_, err = rule.InterpolateMessage("running {{ .maxReplicas }} replicas", map[string]interface{}{"minReplicas": float64(3)})
+	assert.NotNil(t, err)
+
+	// the messages of rules without parameters aren't templates
+	message, err = (&DefaultRuleDefinition{}).InterpolateMessage("use {{ braces }}", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "use {{ braces }}", message)
+}
+
+func TestDefaultRulesParameters(t *testing.T) {
+	defaultRules, err := YAMLToStruct(defaultRulesFileContent)
+	assert.Nil(t, err)
+
+	for _, rule := range defaultRules.Rules {
+		if len(rule.Parameters) == 0 {
+			continue
+		}
+		values, err := rule.ResolveParameters(nil)
+		assert.Nil(t, err)
+		for _, parameter := range rule.Parameters {
+			assert.Nil(t, parameter.ValidateValue(parameter.Default), rule.UniqueName)
+		}
+
+		// the schema of a parameterized rule holds the default values of its parameters
+		schema, err := rule.GetSchemaWithParameters(values)
+		assert.Nil(t, err, rule.UniqueName)
+		assert.Equal(t, rule.Schema, schema, rule.UniqueName)
+
+		_, err = rule.InterpolateMessage(rule.MessageOnFailure, values)
+		assert.Nil(t, err, rule.UniqueName)
+	}
+}
@@ -4,16 +4,22 @@ import (
 	"encoding/json"
 	"os"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
 	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/datreeio/datree/pkg/baseline"
 	"github.com/datreeio/datree/pkg/ciContext"
 	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/exceptions"
 	"github.com/datreeio/datree/pkg/extractor"
 	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
 	extensions "github.com/datreeio/datree/pkg/jsonSchemaValidator/extensions"
 	"github.com/datreeio/datree/pkg/utils"
 	"github.com/mikefarah/yq/v4/pkg/yqlib"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/op/go-logging.v1"
@@ -28,11 +34,16 @@ type CLIClient interface {
 	SendEvaluationResult(request *cliClient.EvaluationResultRequest) (*cliClient.SendEvaluationResultsResponse, error)
 }
 
+// rules evaluation is cpu bound, so there is no gain in running more workers than cpus
+var evaluationConcurrency = runtime.NumCPU()
+
 type Evaluator struct {
 	cliClient           CLIClient
 	ciContext           *ciContext.CIContext
 	jsonSchemaValidator *jsonSchemaValidator.JSONSchemaValidator
 	yqlibEvaluator      yqlib.Evaluator
+	// yqlib doesn't document its evaluator as safe for concurrent use
+	yqlibEvaluatorMutex sync.Mutex
 }
 
 func New(c CLIClient, ciContext *ciContext.CIContext) *Evaluator {
@@ -59,9 +70,12 @@ type FailedRulesByFiles map[string]map[string]*cliClient.FailedRule
 type EvaluationResultsSummery struct {
 	TotalFailedRules  int
 	TotalSkippedRules int
+	TotalWarningRules int
 	TotalPassedRules  int
-	FilesCount        int
-	FilesPassedCount  int
+	// TotalFailedRulesBySeverity breaks TotalFailedRules down by the severity of the rules
+	TotalFailedRulesBySeverity map[string]int
+	FilesCount                 int
+	FilesPassedCount           int
 }
 
 type EvaluationResults struct {
@@ -72,6 +86,7 @@ type EvaluationResults struct {
 type FormattedResults struct {
 	EvaluationResults               *EvaluationResults
 	NonInteractiveEvaluationResults *NonInteractiveEvaluationResults
+	BaselineResult                  *baseline.Result
 }
 
 type EvaluationRequestData struct {
@@ -122,6 +137,12 @@ type PolicyCheckData struct {
 	PolicyName          string
 	Policy              policy_factory.Policy
 	Verbose             bool
+	// Baseline hides the violations that are in it from the results
+	Baseline *baseline.Baseline
+	// WriteBaseline asks for a baseline of all the violations of the run, including the ones that are hidden by Baseline
+	WriteBaseline bool
+	// Exceptions skip the failures of the rules and resources they match
+	Exceptions *exceptions.Exceptions
 }
 
 type PolicyCheckResultData struct {
@@ -130,16 +151,26 @@ type PolicyCheckResultData struct {
 	FilesData        []cliClient.FileData
 	RawResults       FailedRulesByFiles
 	RulesCount       int
+	// NewBaseline is set when PolicyCheckData.WriteBaseline is set
+	NewBaseline *baseline.Baseline
 }
 
 func (e *Evaluator) Evaluate(policyCheckData PolicyCheckData) (PolicyCheckResultData, error) {
 	rulesCount := len(policyCheckData.Policy.Rules)
 
+	// the results of rendered configurations cite the files they were rendered from.
+	// The files arrive in the order the concurrent extraction finished them, so they are sorted to keep every output the same between runs
+	policyCheckData.FilesConfigurations = sortFilesConfigurations(groupConfigurationsBySource(sortFilesConfigurations(policyCheckData.FilesConfigurations)))
+
 	if len(policyCheckData.FilesConfigurations) == 0 {
-		return PolicyCheckResultData{FormattedResults{}, []cliClient.RuleData{}, []cliClient.FileData{}, FailedRulesByFiles{}, rulesCount}, nil
+		var newBaseline *baseline.Baseline
+		if policyCheckData.WriteBaseline {
+			newBaseline = baseline.New(FailedRulesByFiles{})
+		}
+		return PolicyCheckResultData{FormattedResults{}, []cliClient.RuleData{}, []cliClient.FileData{}, FailedRulesByFiles{}, rulesCount, newBaseline}, nil
 	}
 
-	emptyPolicyCheckResult := PolicyCheckResultData{FormattedResults{}, []cliClient.RuleData{}, []cliClient.FileData{}, nil, 0}
+	emptyPolicyCheckResult := PolicyCheckResultData{FormattedResults{}, []cliClient.RuleData{}, []cliClient.FileData{}, nil, 0, nil}
 
 	var filesData []cliClient.FileData
 	for _, filesConfiguration := range policyCheckData.FilesConfigurations {
@@ -151,19 +182,39 @@ func (e *Evaluator) Evaluate(policyCheckData PolicyCheckData) (PolicyCheckResult
 		rulesData = append(rulesData, cliClient.RuleData{Identifier: rule.RuleIdentifier, Name: rule.RuleName})
 	}
 
-	// map of files paths to map of rules to failed rule data
-	failedRulesByFiles := make(FailedRulesByFiles)
-	for _, filesConfiguration := range policyCheckData.FilesConfigurations {
-		for _, configuration := range filesConfiguration.Configurations {
-			// add all configurations skipped rules to the skipped rules map
-			err := e.evaluateConfiguration(failedRulesByFiles, policyCheckData, filesConfiguration.FileName, configuration)
-			if err != nil {
-				return emptyPolicyCheckResult, err
-			}
-		}
+	configurationsToEvaluate, err := getConfigurationsToEvaluate(policyCheckData.FilesConfigurations)
+	if err != nil {
+		return emptyPolicyCheckResult, err
+	}
+
+	// cross resource rules see every configuration of the run
+	var resources []interface{}
+	for _, configurationToEvaluate := range configurationsToEvaluate {
+		resources = append(resources, configurationToEvaluate.configurationJson)
+	}
+
+	compiledPolicy, err := policy_factory.CompilePolicy(policyCheckData.Policy, extensions.NewInventory(resources))
+	if err != nil {
+		return emptyPolicyCheckResult, err
+	}
+
+	failedRulesByFiles, err := e.evaluateFilesConfigurations(compiledPolicy, policyCheckData.Exceptions, configurationsToEvaluate)
+	if err != nil {
+		return emptyPolicyCheckResult, err
+	}
+
+	var newBaseline *baseline.Baseline
+	if policyCheckData.WriteBaseline {
+		newBaseline = baseline.New(failedRulesByFiles)
 	}
 
 	formattedResults := FormattedResults{}
+	if policyCheckData.Baseline != nil {
+		var baselineResult baseline.Result
+		failedRulesByFiles, baselineResult = policyCheckData.Baseline.Apply(failedRulesByFiles)
+		formattedResults.BaselineResult = &baselineResult
+	}
+
 	formattedResults.EvaluationResults = e.formatEvaluationResults(failedRulesByFiles, len(policyCheckData.FilesConfigurations), rulesCount)
 
 	nonInteractiveEvaluationData := nonInteractiveEvaluationData{
@@ -176,89 +227,320 @@ func (e *Evaluator) Evaluate(policyCheckData PolicyCheckData) (PolicyCheckResult
 
 	formattedResults.NonInteractiveEvaluationResults = e.formatNonInteractiveEvaluationResults(nonInteractiveEvaluationData)
 
-	return PolicyCheckResultData{formattedResults, rulesData, filesData, failedRulesByFiles, rulesCount}, nil
+	return PolicyCheckResultData{formattedResults, rulesData, filesData, failedRulesByFiles, rulesCount, newBaseline}, nil
+}
+
+// groupConfigurationsBySource moves the configurations that were rendered from a source, like a helm chart template, to a file of their source
+func groupConfigurationsBySource(filesConfigurations []*extractor.FileConfigurations) []*extractor.FileConfigurations {
+	var groupedFilesConfigurations []*extractor.FileConfigurations
+	sourcesFilesConfigurations := make(map[string]*extractor.FileConfigurations)
+	for _, filesConfiguration := range filesConfigurations {
+		var configurations []extractor.Configuration
+		for _, configuration := range filesConfiguration.Configurations {
+			if configuration.Source == "" {
+				configurations = append(configurations, configuration)
+				continue
+			}
+
+			sourceFilesConfiguration, ok := sourcesFilesConfigurations[configuration.Source]
+			if !ok {
+				sourceFilesConfiguration = &extractor.FileConfigurations{FileName: configuration.Source}
+				sourcesFilesConfigurations[configuration.Source] = sourceFilesConfiguration
+				groupedFilesConfigurations = append(groupedFilesConfigurations, sourceFilesConfiguration)
+			}
+			sourceFilesConfiguration.Configurations = append(sourceFilesConfiguration.Configurations, configuration)
+		}
+
+		// a file whose configurations were all rendered from sources is replaced by the files of its sources
+		if len(configurations) > 0 || len(configurations) == len(filesConfiguration.Configurations) {
+			groupedFilesConfigurations = append(groupedFilesConfigurations, &extractor.FileConfigurations{FileName: filesConfiguration.FileName, Configurations: configurations})
+		}
+	}
+	return groupedFilesConfigurations
+}
+
+// sortFilesConfigurations returns a copy of the files configurations sorted by file name
+func sortFilesConfigurations(filesConfigurations []*extractor.FileConfigurations) []*extractor.FileConfigurations {
+	sortedFilesConfigurations := append([]*extractor.FileConfigurations{}, filesConfigurations...)
+	sort.SliceStable(sortedFilesConfigurations, func(i, j int) bool {
+		return sortedFilesConfigurations[i].FileName < sortedFilesConfigurations[j].FileName
+	})
+	return sortedFilesConfigurations
+}
+
+type configurationToEvaluate struct {
+	fileName          string
+	configuration     extractor.Configuration
+	configurationJson interface{}
 }
 
-func (e *Evaluator) evaluateConfiguration(failedRulesByFiles FailedRulesByFiles, policyCheckData PolicyCheckData, fileName string, configuration extractor.Configuration) error {
+// getConfigurationsToEvaluate unmarshals the payload of every configuration once, it's shared by all the rules
+func getConfigurationsToEvaluate(filesConfigurations []*extractor.FileConfigurations) ([]configurationToEvaluate, error) {
+	var configurationsToEvaluate []configurationToEvaluate
+	for _, filesConfiguration := range filesConfigurations {
+		for _, configuration := range filesConfiguration.Configurations {
+			var configurationJson interface{}
+			if err := json.Unmarshal(configuration.Payload, &configurationJson); err != nil {
+				return nil, err
+			}
+			configurationsToEvaluate = append(configurationsToEvaluate, configurationToEvaluate{fileName: filesConfiguration.FileName, configuration: configuration, configurationJson: configurationJson})
+		}
+	}
+	return configurationsToEvaluate, nil
+}
+
+// evaluateFilesConfigurations evaluates the configurations on a bounded pool of workers.
+// Each configuration's result is kept by its index and merged in input order, so the results don't depend on scheduling
+func (e *Evaluator) evaluateFilesConfigurations(compiledPolicy *policy_factory.CompiledPolicy, policyExceptions *exceptions.Exceptions, configurationsToEvaluate []configurationToEvaluate) (FailedRulesByFiles, error) {
+	failedRulesByConfiguration := make([]map[string]*cliClient.FailedRule, len(configurationsToEvaluate))
+	errorsByConfiguration := make([]error, len(configurationsToEvaluate))
+
+	indexesChan := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < evaluationConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexesChan {
+				failedRulesByConfiguration[index], errorsByConfiguration[index] = e.evaluateConfiguration(compiledPolicy, policyExceptions, configurationsToEvaluate[index])
+			}
+		}()
+	}
+
+	for index := range configurationsToEvaluate {
+		indexesChan <- index
+	}
+	close(indexesChan)
+	wg.Wait()
+
+	// map of files paths to map of rules to failed rule data
+	failedRulesByFiles := make(FailedRulesByFiles)
+	for index, configurationToEvaluate := range configurationsToEvaluate {
+		if errorsByConfiguration[index] != nil {
+			return nil, errorsByConfiguration[index]
+		}
+
+		for ruleIdentifier, failedRule := range failedRulesByConfiguration[index] {
+			addFailedRule(failedRulesByFiles, configurationToEvaluate.fileName, ruleIdentifier, failedRule)
+		}
+	}
+
+	return failedRulesByFiles, nil
+}
+
+// evaluateConfiguration returns the failed rules of a single configuration, mapped by rule identifier
+func (e *Evaluator) evaluateConfiguration(compiledPolicy *policy_factory.CompiledPolicy, policyExceptions *exceptions.Exceptions, configurationToEvaluate configurationToEvaluate) (map[string]*cliClient.FailedRule, error) {
+	configuration := configurationToEvaluate.configuration
+	configurationJson := configurationToEvaluate.configurationJson
 	skipAnnotations := extractSkipAnnotations(configuration)
 
-	for _, rule := range policyCheckData.Policy.Rules {
-		failedRule, err := e.evaluateRule(rule, configuration.Payload, configuration.MetadataName, configuration.Kind, skipAnnotations, configuration.YamlNode)
+	selectedResource := policy_factory.NewSelectedResource(configurationJson, configurationToEvaluate.fileName)
+	configurationNamespace := getConfigurationNamespace(configurationJson)
+	resourceExceptions := getResourceExceptions(policyExceptions, configuration, selectedResource)
+
+	failedRules := make(map[string]*cliClient.FailedRule)
+	for _, rule := range compiledPolicy.Rules {
+		if !rule.AppliesTo(selectedResource) {
+			continue
+		}
+
+		failedRule, err := e.evaluateRule(rule, compiledPolicy.Schemas[rule.RuleIdentifier], configurationJson, configuration.MetadataName, configurationNamespace, configuration.Kind, skipAnnotations, resourceExceptions, configuration.YamlNode)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if failedRule == nil {
 			continue
 		}
-		addFailedRule(failedRulesByFiles, fileName, rule.RuleIdentifier, failedRule)
+		failedRules[rule.RuleIdentifier] = failedRule
 	}
 
-	return nil
+	// checked after all the rules ran, so the annotations that skipped a failure are marked as used
+	if skipAnnotationsWarning := e.getSkipAnnotationsWarning(skipAnnotations, configuration.MetadataName, configurationNamespace, configuration.Kind, configuration.YamlNode); skipAnnotationsWarning != nil {
+		failedRules[SkipAnnotationsRuleIdentifier] = skipAnnotationsWarning
+	}
+
+	// the failures of a rendered configuration point to its document among the documents of its source
+	if configuration.Source != "" {
+		documentIndex := configuration.DocumentIndex
+		for _, failedRule := range failedRules {
+			for index := range failedRule.Configurations {
+				failedRule.Configurations[index].DocumentIndex = &documentIndex
+			}
+		}
+	}
+
+	if configuration.RenderedFrom != "" {
+		for _, failedRule := range failedRules {
+			for index := range failedRule.Configurations {
+				failedRule.Configurations[index].RenderedFrom = configuration.RenderedFrom
+			}
+		}
+	}
+
+	return failedRules, nil
 }
 
-func (e *Evaluator) evaluateRule(rule policy_factory.RuleWithSchema, configurationJson []byte, configurationName string, configurationKind string, skipAnnotations map[string]string, yamlNode yaml.Node) (*cliClient.FailedRule, error) {
-	ruleSchemaJson, err := json.Marshal(rule.Schema)
-	if err != nil {
-		return nil, err
+// getResourceExceptions returns the exceptions that aren't expired and match the resource, whatever their rule is
+func getResourceExceptions(policyExceptions *exceptions.Exceptions, configuration extractor.Configuration, selectedResource policy_factory.SelectedResource) []*exceptions.Exception {
+	if policyExceptions == nil {
+		return nil
 	}
 
-	validationResult, err := e.jsonSchemaValidator.ValidateYamlSchema(string(ruleSchemaJson), string(configurationJson))
+	var resourceExceptions []*exceptions.Exception
+	for index := range policyExceptions.Exceptions {
+		exception := &policyExceptions.Exceptions[index]
+		if !exception.IsExpired() && exception.MatchesResource(configuration.Kind, selectedResource.Namespace, configuration.MetadataName, selectedResource.FilePaths) {
+			resourceExceptions = append(resourceExceptions, exception)
+		}
+	}
+	return resourceExceptions
+}
 
-	if err != nil {
-		return nil, err
+func getRuleException(resourceExceptions []*exceptions.Exception, ruleIdentifier string) *exceptions.Exception {
+	for _, exception := range resourceExceptions {
+		if exception.MatchesRule(ruleIdentifier) {
+			return exception
+		}
 	}
+	return nil
+}
 
-	occurrences := len(validationResult)
-	skipMessage, skipRuleExists := skipAnnotations[SKIP_RULE_PREFIX+rule.RuleIdentifier]
+// getConfigurationNamespace returns the namespace as written in the configuration, empty when it's not set
+func getConfigurationNamespace(configurationJson interface{}) string {
+	configuration, _ := configurationJson.(map[string]interface{})
+	metadata, _ := configuration["metadata"].(map[string]interface{})
+	namespace, _ := metadata["namespace"].(string)
+	return namespace
+}
 
-	if occurrences < 1 && !skipRuleExists {
-		return nil, nil
+func (e *Evaluator) evaluateRule(rule policy_factory.RuleWithSchema, ruleSchema *jsonschema.Schema, configurationJson interface{}, configurationName string, configurationNamespace string, configurationKind string, skipAnnotations []*skipAnnotation, resourceExceptions []*exceptions.Exception, yamlNode yaml.Node) (*cliClient.FailedRule, error) {
+	validationResult, err := e.jsonSchemaValidator.ValidateCompiledSchema(ruleSchema, configurationJson)
+
+	if err != nil {
+		return nil, err
 	}
 
-	configuration := cliClient.Configuration{
-		Name:                      configurationName,
-		Kind:                      configurationKind,
-		Occurrences:               occurrences,
-		IsSkipped:                 false,
-		SkipMessage:               "",
-		FailureLocations:          []cliClient.FailureLocation{},
-		ValidationFailureMessages: []string{},
+	if len(validationResult) < 1 {
+		return nil, nil
 	}
 
 	var validationFailureMessages []string
+	var failureLocations []cliClient.FailureLocation
 	for _, detailedResult := range validationResult {
 		if strings.Contains(detailedResult.KeywordLocation, extensions.CustomKeyValidationErrorKeyPath) {
 			validationFailureMessages = append(validationFailureMessages, detailedResult.Error)
 		}
 		failedErrorLine, failedErrorColumn := e.getFailedRuleLineAndColumn(detailedResult.InstanceLocation, yamlNode)
 
-		failureLocation := cliClient.FailureLocation{
+		failureLocations = append(failureLocations, cliClient.FailureLocation{
 			SchemaPath:        detailedResult.InstanceLocation,
 			FailedErrorLine:   failedErrorLine,
 			FailedErrorColumn: failedErrorColumn,
-		}
+		})
+	}
 
-		configuration.FailureLocations = append(configuration.FailureLocations, failureLocation)
+	// a skip annotation can skip only some of the failure locations, e.g. the failures of a single container
+	ruleSkipAnnotations := getSkipAnnotationsForRule(skipAnnotations, rule)
+	var failingLocations []cliClient.FailureLocation
+	var skippedLocations []cliClient.FailureLocation
+	var skipMessages []string
+	for _, failureLocation := range failureLocations {
+		skippingAnnotation := getSkippingAnnotation(ruleSkipAnnotations, failureLocation, configurationJson)
+		if skippingAnnotation == nil {
+			failingLocations = append(failingLocations, failureLocation)
+			continue
+		}
+		skippedLocations = append(skippedLocations, failureLocation)
+		if !skippingAnnotation.isUsed {
+			skippingAnnotation.isUsed = true
+			skipMessages = append(skipMessages, skippingAnnotation.message)
+		}
 	}
 
-	configuration.ValidationFailureMessages = validationFailureMessages
+	var configurations []cliClient.Configuration
+
+	// the failures that the annotations didn't skip are skipped by the first exception of the rule
+	if exception := getRuleException(resourceExceptions, rule.RuleIdentifier); exception != nil && len(failingLocations) > 0 {
+		configurations = append(configurations, cliClient.Configuration{
+			Name:                      configurationName,
+			Namespace:                 configurationNamespace,
+			Kind:                      configurationKind,
+			Occurrences:               len(failingLocations),
+			IsSkipped:                 true,
+			SkipMessage:               exception.SkipMessage(),
+			FailureLocations:          failingLocations,
+			ValidationFailureMessages: validationFailureMessages,
+			Exception:                 exception,
+		})
+		failingLocations = nil
+	}
 
-	if skipRuleExists {
-		configuration.IsSkipped = true
-		configuration.SkipMessage = skipMessage
+	if len(failingLocations) > 0 {
+		configurations = append(configurations, cliClient.Configuration{
+			Name:                      configurationName,
+			Namespace:                 configurationNamespace,
+			Kind:                      configurationKind,
+			Occurrences:               len(failingLocations),
+			FailureLocations:          failingLocations,
+			ValidationFailureMessages: validationFailureMessages,
+		})
+	}
+	if len(skippedLocations) > 0 {
+		configurations = append(configurations, cliClient.Configuration{
+			Name:                      configurationName,
+			Namespace:                 configurationNamespace,
+			Kind:                      configurationKind,
+			Occurrences:               len(skippedLocations),
+			IsSkipped:                 true,
+			SkipMessage:               strings.Join(skipMessages, "; "),
+			FailureLocations:          skippedLocations,
+			ValidationFailureMessages: validationFailureMessages,
+		})
 	}
 
 	failedRule := &cliClient.FailedRule{
 		Name:             rule.RuleName,
 		DocumentationUrl: rule.DocumentationUrl,
 		MessageOnFailure: rule.MessageOnFailure,
-		Configurations:   []cliClient.Configuration{configuration},
+		Configurations:   configurations,
+		IsWarning:        rule.IsWarning,
+		Severity:         rule.Severity,
 	}
 
 	return failedRule, nil
 }
 
+// getSkipAnnotationsWarning returns a warning listing the expired, invalid and unused skip annotations of a configuration, or nil when there are none
+func (e *Evaluator) getSkipAnnotationsWarning(skipAnnotations []*skipAnnotation, configurationName string, configurationNamespace string, configurationKind string, yamlNode yaml.Node) *cliClient.FailedRule {
+	problems := getSkipAnnotationsProblems(skipAnnotations)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	// resources with only inline ignore comments may have no annotations
+	schemaPath := skipAnnotationsSchemaPath
+	failedErrorLine, failedErrorColumn := e.getFailedRuleLineAndColumn(schemaPath, yamlNode)
+	if failedErrorLine == 0 {
+		schemaPath = "/metadata"
+		failedErrorLine, failedErrorColumn = e.getFailedRuleLineAndColumn(schemaPath, yamlNode)
+	}
+	return &cliClient.FailedRule{
+		Name:             "Ensure skip annotations are valid and in use",
+		MessageOnFailure: "Remove or update the skip annotations and ignore comments that no longer skip any failure",
+		IsWarning:        true,
+		Severity:         policy_factory.SeverityInfo,
+		Configurations: []cliClient.Configuration{{
+			Name:                      configurationName,
+			Namespace:                 configurationNamespace,
+			Kind:                      configurationKind,
+			Occurrences:               1,
+			FailureLocations:          []cliClient.FailureLocation{{SchemaPath: schemaPath, FailedErrorLine: failedErrorLine, FailedErrorColumn: failedErrorColumn}},
+			ValidationFailureMessages: problems,
+		}},
+	}
+}
+
 type nonInteractiveEvaluationData struct {
 	FormattedEvaluationResults *EvaluationResults
 	EvaluationResults          FailedRulesByFiles
@@ -281,12 +563,14 @@ func (e *Evaluator) formatNonInteractiveEvaluationResults(nonInteractiveEvaluati
 
 	nonInteractiveEvaluationResults := NonInteractiveEvaluationResults{}
 
-	for fileName, rules := range fileNameRuleMapper {
+	for _, fileName := range getSortedKeys(fileNameRuleMapper) {
+		rules := fileNameRuleMapper[fileName]
 		formattedEvaluationResults := FormattedEvaluationResults{}
 		formattedEvaluationResults.FileName = fileName
 
-		for _, rule := range rules {
-			ruleResult := RuleResult{Identifier: ruleMapper[rule.Identifier], Name: rule.Name, MessageOnFailure: rule.MessageOnFailure, OccurrencesDetails: rule.OccurrencesDetails}
+		for _, ruleIdentifier := range getSortedKeys(rules) {
+			rule := rules[ruleIdentifier]
+			ruleResult := RuleResult{Identifier: ruleMapper[rule.Identifier], Name: rule.Name, MessageOnFailure: rule.MessageOnFailure, OccurrencesDetails: rule.OccurrencesDetails, IsWarning: rule.IsWarning, Severity: rule.Severity}
 			if nonInteractiveEvaluationData.Verbose {
 				ruleResult.DocumentationUrl = rule.DocumentationUrl
 			}
@@ -302,11 +586,13 @@ func (e *Evaluator) formatNonInteractiveEvaluationResults(nonInteractiveEvaluati
 		)
 	}
 	nonInteractiveEvaluationResults.PolicySummary = &PolicySummary{
-		PolicyName:         nonInteractiveEvaluationData.PolicyName,
-		TotalRulesInPolicy: nonInteractiveEvaluationData.TotalRulesInPolicy,
-		TotalRulesFailed:   nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalFailedRules,
-		TotalSkippedRules:  nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalSkippedRules,
-		TotalPassedCount:   nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalPassedRules,
+		PolicyName:                 nonInteractiveEvaluationData.PolicyName,
+		TotalRulesInPolicy:         nonInteractiveEvaluationData.TotalRulesInPolicy,
+		TotalRulesFailed:           nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalFailedRules,
+		TotalSkippedRules:          nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalSkippedRules,
+		TotalPassedCount:           nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalPassedRules,
+		TotalWarningRules:          nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalWarningRules,
+		TotalRulesFailedBySeverity: newFailedRulesBySeverity(nonInteractiveEvaluationData.FormattedEvaluationResults.Summary.TotalFailedRulesBySeverity),
 	}
 
 	return &nonInteractiveEvaluationResults
@@ -317,6 +603,10 @@ func (e *Evaluator) formatEvaluationResults(evaluationResults FailedRulesByFiles
 
 	totalFailedCount := 0
 	totalSkippedCount := 0
+	totalWarningCount := 0
+	// warnings that are not policy rules, e.g. about skip annotations, are not part of the rules * files count
+	nonPolicyWarningCount := 0
+	totalFailedCountBySeverity := make(map[string]int)
 	failedFilesCount := len(evaluationResults)
 
 	for filePath := range evaluationResults {
@@ -333,6 +623,8 @@ func (e *Evaluator) formatEvaluationResults(evaluationResults FailedRulesByFiles
 					DocumentationUrl:   failedRule.DocumentationUrl,
 					MessageOnFailure:   failedRule.MessageOnFailure,
 					OccurrencesDetails: []OccurrenceDetails{},
+					IsWarning:          failedRule.IsWarning,
+					Severity:           failedRule.Severity,
 				}
 			}
 
@@ -347,6 +639,9 @@ func (e *Evaluator) formatEvaluationResults(evaluationResults FailedRulesByFiles
 						SkipMessage:               configuration.SkipMessage,
 						FailureLocations:          configuration.FailureLocations,
 						ValidationFailureMessages: configuration.ValidationFailureMessages,
+						Exception:                 configuration.Exception,
+						DocumentIndex:             configuration.DocumentIndex,
+						RenderedFrom:              configuration.RenderedFrom,
 					},
 				)
 			}
@@ -354,25 +649,36 @@ func (e *Evaluator) formatEvaluationResults(evaluationResults FailedRulesByFiles
 
 		allRulesAreSkipped := true
 
-		for _, rule := range mapper[filePath] {
+		for ruleIdentifier, rule := range mapper[filePath] {
+			if ruleIdentifier == SkipAnnotationsRuleIdentifier {
+				nonPolicyWarningCount++
+			}
 			skippedOccurrences := 0
 			totalOccurrences := len(rule.OccurrencesDetails)
 
 			for _, occurrence := range rule.OccurrencesDetails {
 				if occurrence.IsSkipped {
 					skippedOccurrences++
-				} else {
+				} else if !rule.IsWarning {
 					allRulesAreSkipped = false
 				}
 			}
 
+			// warnings don't fail the file, so a rule with a warning counts as a warning instead of a failure
 			if totalOccurrences == skippedOccurrences {
 				totalSkippedCount++
+			} else if rule.IsWarning {
+				totalWarningCount++
+				if skippedOccurrences >= 1 {
+					totalSkippedCount++
+				}
 			} else if skippedOccurrences >= 1 {
 				totalSkippedCount++
 				totalFailedCount++
+				totalFailedCountBySeverity[getSeverity(rule)]++
 			} else {
 				totalFailedCount++
+				totalFailedCountBySeverity[getSeverity(rule)]++
 			}
 		}
 
@@ -384,19 +690,39 @@ func (e *Evaluator) formatEvaluationResults(evaluationResults FailedRulesByFiles
 	results := &EvaluationResults{
 		FileNameRuleMapper: mapper,
 		Summary: EvaluationResultsSummery{
-			TotalFailedRules:  totalFailedCount,
-			TotalSkippedRules: totalSkippedCount,
-			TotalPassedRules:  (rulesCount * filesCount) - (totalFailedCount + totalSkippedCount),
-			FilesCount:        filesCount,
-			FilesPassedCount:  filesCount - failedFilesCount,
+			TotalFailedRules:           totalFailedCount,
+			TotalSkippedRules:          totalSkippedCount,
+			TotalWarningRules:          totalWarningCount,
+			TotalPassedRules:           (rulesCount * filesCount) - (totalFailedCount + totalSkippedCount + totalWarningCount - nonPolicyWarningCount),
+			TotalFailedRulesBySeverity: totalFailedCountBySeverity,
+			FilesCount:                 filesCount,
+			FilesPassedCount:           filesCount - failedFilesCount,
 		},
 	}
 
 	return results
 }
 
+// getSeverity returns the severity of the rule, rules without a severity have the default severity
+func getSeverity(rule *Rule) string {
+	if rule.Severity == "" {
+		return policy_factory.DefaultSeverity
+	}
+	return rule.Severity
+}
+
 type Result = gojsonschema.Result
 
+// getSortedKeys is used to keep the outputs in a stable order when iterating over maps
+func getSortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func addFailedRule(currentFailedRulesByFiles FailedRulesByFiles, fileName string, ruleIdentifier string, failedRule *cliClient.FailedRule) {
 	fileData, ok := currentFailedRulesByFiles[fileName]
 
@@ -412,24 +738,14 @@ func addFailedRule(currentFailedRulesByFiles FailedRulesByFiles, fileName string
 	}
 }
 
-func extractSkipAnnotations(configuration extractor.Configuration) map[string]string {
-	skipAnnotations := make(map[string]string)
-
-	for annotationKey, annotationValue := range configuration.Annotations {
-		if strings.Contains(annotationKey, SKIP_RULE_PREFIX) {
-			skipAnnotations[annotationKey] = annotationValue.(string)
-		}
-	}
-
-	return skipAnnotations
-}
-
 func (e *Evaluator) getFailedRuleLineAndColumn(schemaPath string, yamlNode yaml.Node) (failedErrorLine int, failedErrorColumn int) {
 
 	instanceLocationYqPath := strings.Replace(schemaPath, "/", ".", -1)
 	instanceLocationYqPath = regexp.MustCompile(`\d+`).ReplaceAllString(instanceLocationYqPath, `[$0]`)
 
+	e.yqlibEvaluatorMutex.Lock()
 	nodeList, err := e.yqlibEvaluator.EvaluateNodes(instanceLocationYqPath, &yamlNode)
+	e.yqlibEvaluatorMutex.Unlock()
 	if err != nil {
 		return
 	}
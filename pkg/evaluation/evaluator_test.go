@@ -4,6 +4,7 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"fmt"
 	"io"
 	"path/filepath"
 	"testing"
@@ -17,9 +18,12 @@ import (
 	"github.com/datreeio/datree/pkg/fileReader"
 	"github.com/datreeio/datree/pkg/utils"
 
+	"github.com/datreeio/datree/pkg/baseline"
 	"github.com/datreeio/datree/pkg/ciContext"
 	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/exceptions"
 	"github.com/datreeio/datree/pkg/extractor"
+	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -170,6 +174,56 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestEvaluateIsDeterministic(t *testing.T) {
+	var filesConfigurations []*extractor.FileConfigurations
+	for _, path := range []string{"../../internal/fixtures/kube/k8s-demo.yaml", "../../internal/fixtures/kube/fail-30.yaml", "../../internal/fixtures/kube/combinations.yaml"} {
+		configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile(path)
+		if invalidFile != nil {
+			t.Fatal(invalidFile.ValidationErrors[0])
+		}
+		filesConfigurations = append(filesConfigurations, &extractor.FileConfigurations{FileName: absolutePath, Configurations: *configurations})
+	}
+
+	defaultRules, err := defaultRules.GetDefaultRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := policy_factory.CreatePolicy(defaultPolicies.GetDefaultPoliciesStruct(), "", "", defaultRules, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evaluateToJson := func(concurrency int, filesConfigurations []*extractor.FileConfigurations) string {
+		defaultConcurrency := evaluationConcurrency
+		evaluationConcurrency = concurrency
+		defer func() { evaluationConcurrency = defaultConcurrency }()
+
+		policyCheckResultData, err := New(&mockCliClient{}, nil).Evaluate(PolicyCheckData{
+			FilesConfigurations: filesConfigurations,
+			PolicyName:          policy.Name,
+			Policy:              policy,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resultsJson, err := json.Marshal([]interface{}{policyCheckResultData.RawResults, policyCheckResultData.FormattedResults.NonInteractiveEvaluationResults, policyCheckResultData.FilesData})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(resultsJson)
+	}
+
+	sequentialResults := evaluateToJson(1, filesConfigurations)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, sequentialResults, evaluateToJson(8, filesConfigurations))
+	}
+
+	// the files arrive in the order their extraction finished
+	reversedFilesConfigurations := []*extractor.FileConfigurations{filesConfigurations[2], filesConfigurations[1], filesConfigurations[0]}
+	assert.Equal(t, sequentialResults, evaluateToJson(8, reversedFilesConfigurations))
+}
+
 //go:embed test_fixtures/FailureLocations.yaml
 var FailureLocationsStr string
 
@@ -195,18 +249,461 @@ func TestGetFailedRuleLineAndColumn(t *testing.T) {
 //go:embed test_fixtures/customRuleWithRegoCodeThatCantBeCompiled.yaml
 var customRuleWithRegoCodeThatCantBeCompiledStr string
 
-func TestEvaluateRuleWithCustomKeyThatIsNotValid(t *testing.T) {
+func TestCompilePolicyWithCustomKeyThatIsNotValid(t *testing.T) {
 	customRuleWithRegoByteArray := []byte(customRuleWithRegoCodeThatCantBeCompiledStr)
 	var customRegoRule defaultPolicies.CustomRule
 	_ = yaml.Unmarshal(customRuleWithRegoByteArray, &customRegoRule)
 	customRuleWithRegoObj := policy_factory.RuleWithSchema{RuleIdentifier: customRegoRule.Identifier, RuleName: customRegoRule.Name, Schema: customRegoRule.Schema, MessageOnFailure: customRegoRule.DefaultMessageOnFailure}
 
+	policyCheckData := PolicyCheckData{
+		FilesConfigurations: newFilesConfigurations("../../internal/fixtures/kube/k8s-demo.yaml"),
+		Policy:              policy_factory.Policy{Name: "Default", Rules: []policy_factory.RuleWithSchema{customRuleWithRegoObj}},
+	}
+
 	mockedCliClient := &mockCliClient{}
 	evaluator := New(mockedCliClient, nil)
 
-	failedRule, _ := evaluator.evaluateRule(customRuleWithRegoObj, []byte(FailureLocationsStr), "test", "Deployment", nil, yaml.Node{})
-	assert.NotEmpty(t, failedRule.Configurations[0].ValidationFailureMessages)
-	assert.Contains(t, failedRule.Configurations[0].ValidationFailureMessages[0], "can't compile rego code")
+	_, err := evaluator.Evaluate(policyCheckData)
+	assert.ErrorContains(t, err, "failed to compile rule CUSTOM_DEPLOYMENT_BILLING_LABEL_EXISTS: can't compile rego code")
+}
+
+//go:embed test_fixtures/customRuleWithRegoViolationMessages.yaml
+var customRuleWithRegoViolationMessagesStr string
+
+func TestEvaluateCustomRegoRuleWithViolationMessages(t *testing.T) {
+	var customRegoRule defaultPolicies.CustomRule
+	err := yaml.Unmarshal([]byte(customRuleWithRegoViolationMessagesStr), &customRegoRule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	customRuleWithRegoObj := policy_factory.RuleWithSchema{RuleIdentifier: customRegoRule.Identifier, RuleName: customRegoRule.Name, Schema: customRegoRule.Schema, MessageOnFailure: customRegoRule.DefaultMessageOnFailure}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/FailureLocations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: []policy_factory.RuleWithSchema{customRuleWithRegoObj}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failedConfiguration := policyCheckResultData.RawResults[absolutePath][customRegoRule.Identifier].Configurations[0]
+	assert.Equal(t, 2, failedConfiguration.Occurrences)
+	assert.Equal(t, []string{"container front-end uses the latest tag", "container rss-reader uses the latest tag"}, failedConfiguration.ValidationFailureMessages)
+	assert.Equal(t, []cliClient.FailureLocation{
+		{SchemaPath: "/spec/template/spec/containers/0/image", FailedErrorLine: 23, FailedErrorColumn: 18},
+		{SchemaPath: "/spec/template/spec/containers/1/image", FailedErrorLine: 39, FailedErrorColumn: 18},
+	}, failedConfiguration.FailureLocations)
+}
+
+func TestEvaluateWarningRule(t *testing.T) {
+	var customRegoRule defaultPolicies.CustomRule
+	err := yaml.Unmarshal([]byte(customRuleWithRegoViolationMessagesStr), &customRegoRule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warningRule := policy_factory.RuleWithSchema{RuleIdentifier: customRegoRule.Identifier, RuleName: customRegoRule.Name, Schema: customRegoRule.Schema, MessageOnFailure: customRegoRule.DefaultMessageOnFailure, IsWarning: true}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/FailureLocations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: []policy_factory.RuleWithSchema{warningRule}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := policyCheckResultData.FormattedResults.EvaluationResults.Summary
+	assert.Equal(t, 1, summary.TotalWarningRules)
+	assert.Equal(t, 0, summary.TotalFailedRules)
+	assert.Equal(t, 0, summary.TotalPassedRules)
+	assert.Equal(t, 1, summary.FilesPassedCount)
+
+	assert.True(t, policyCheckResultData.RawResults[absolutePath][customRegoRule.Identifier].IsWarning)
+	nonInteractiveResults := policyCheckResultData.FormattedResults.NonInteractiveEvaluationResults
+	assert.True(t, nonInteractiveResults.FormattedEvaluationResults[0].RuleResults[0].IsWarning)
+	assert.Equal(t, 1, nonInteractiveResults.PolicySummary.TotalWarningRules)
+}
+
+func TestFormatEvaluationResultsWithPartiallySkippedRule(t *testing.T) {
+	evaluationResults := FailedRulesByFiles{
+		"manifests.yaml": {
+			"MISSING_LABELS": &cliClient.FailedRule{
+				Name: "Ensure labels are set",
+				Configurations: []cliClient.Configuration{
+					{Name: "web", Kind: "Deployment", Occurrences: 1},
+					{Name: "worker", Kind: "Deployment", Occurrences: 1, IsSkipped: true, SkipMessage: "legacy"},
+				},
+			},
+		},
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	results := evaluator.formatEvaluationResults(evaluationResults, 1, 1)
+	assert.Equal(t, 1, results.Summary.TotalFailedRules)
+	assert.Equal(t, 1, results.Summary.TotalSkippedRules)
+	assert.Equal(t, 0, results.Summary.FilesPassedCount)
+}
+
+//go:embed test_fixtures/crossResourceCustomRules.yaml
+var crossResourceCustomRulesStr string
+
+func TestEvaluateCrossResourceRules(t *testing.T) {
+	var customRules []defaultPolicies.CustomRule
+	err := yaml.Unmarshal([]byte(crossResourceCustomRulesStr), &customRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rules []policy_factory.RuleWithSchema
+	for _, customRule := range customRules {
+		rules = append(rules, policy_factory.RuleWithSchema{RuleIdentifier: customRule.Identifier, RuleName: customRule.Name, Schema: customRule.Schema, MessageOnFailure: customRule.DefaultMessageOnFailure})
+	}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/crossResourceConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: rules},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the violations are attributed to the resource under test,
+	// and the checkout deployment fails because its PodDisruptionBudget is in another namespace
+	missingPDB := policyCheckResultData.RawResults[absolutePath]["CUSTOM_DEPLOYMENT_MISSING_PDB"].Configurations
+	assert.Equal(t, 2, len(missingPDB))
+	assert.Equal(t, "backend", missingPDB[0].Name)
+	assert.Equal(t, []string{"deployment backend has no PodDisruptionBudget"}, missingPDB[0].ValidationFailureMessages)
+	assert.Equal(t, "checkout", missingPDB[1].Name)
+	assert.Equal(t, "payments", missingPDB[1].Namespace)
+	assert.Equal(t, []string{"deployment checkout has no PodDisruptionBudget"}, missingPDB[1].ValidationFailureMessages)
+
+	selectorNotMatched := policyCheckResultData.RawResults[absolutePath]["CUSTOM_SERVICE_SELECTOR_NOT_MATCHED"].Configurations
+	assert.Equal(t, 1, len(selectorNotMatched))
+	assert.Equal(t, "payments", selectorNotMatched[0].Name)
+	assert.Equal(t, []string{"service payments selects no Deployment"}, selectorNotMatched[0].ValidationFailureMessages)
+}
+
+func TestEvaluateRulesWithSelectors(t *testing.T) {
+	missingLabelsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{"required": []interface{}{"labels"}},
+		},
+	}
+	rules := []policy_factory.RuleWithSchema{
+		{
+			RuleIdentifier:   "SCOPED_MISSING_LABELS",
+			RuleName:         "Ensure labels are set on workloads outside kube-system",
+			Schema:           missingLabelsSchema,
+			MessageOnFailure: "Add labels",
+			Match:            &defaultPolicies.RuleSelector{Kinds: []string{"Deployment"}},
+			Exclude:          &defaultPolicies.RuleSelector{Namespaces: []string{"kube-*"}},
+		},
+		{
+			RuleIdentifier:   "EXCLUDED_FILE_MISSING_LABELS",
+			RuleName:         "Ensure labels are set outside the test fixtures",
+			Schema:           missingLabelsSchema,
+			MessageOnFailure: "Add labels",
+			Exclude:          &defaultPolicies.RuleSelector{Files: []string{"**/test_fixtures/*.yaml"}},
+		},
+	}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/ruleSelectorConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: rules},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scopedRuleResult := policyCheckResultData.RawResults[absolutePath]["SCOPED_MISSING_LABELS"]
+	assert.Equal(t, 1, len(scopedRuleResult.Configurations))
+	assert.Equal(t, "web", scopedRuleResult.Configurations[0].Name)
+
+	_, ok := policyCheckResultData.RawResults[absolutePath]["EXCLUDED_FILE_MISSING_LABELS"]
+	assert.False(t, ok)
+}
+
+func TestEvaluateRenderedConfigurations(t *testing.T) {
+	rules := []policy_factory.RuleWithSchema{{
+		RuleIdentifier:   "MISSING_LABELS",
+		RuleName:         "Ensure labels are set",
+		Schema:           map[string]interface{}{"properties": map[string]interface{}{"metadata": map[string]interface{}{"required": []interface{}{"labels"}}}},
+		MessageOnFailure: "Add labels",
+	}}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/helmRenderedConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: rules},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the results cite the chart templates instead of the rendered file, sorted by the template path
+	assert.Equal(t, []cliClient.FileData{
+		{FilePath: "web/templates/deployment.yaml", ConfigurationsCount: 2},
+		{FilePath: "web/templates/service.yaml", ConfigurationsCount: 1},
+	}, policyCheckResultData.FilesData)
+	_, ok := policyCheckResultData.RawResults[absolutePath]
+	assert.False(t, ok)
+
+	serviceResult := policyCheckResultData.RawResults["web/templates/service.yaml"]["MISSING_LABELS"]
+	assert.Equal(t, 0, *serviceResult.Configurations[0].DocumentIndex)
+	assert.Equal(t, "", serviceResult.Configurations[0].RenderedFrom)
+
+	deploymentResult := policyCheckResultData.RawResults["web/templates/deployment.yaml"]["MISSING_LABELS"]
+	assert.Equal(t, 1, len(deploymentResult.Configurations))
+	assert.Equal(t, "worker", deploymentResult.Configurations[0].Name)
+	assert.Equal(t, 1, *deploymentResult.Configurations[0].DocumentIndex)
+	assert.Equal(t, "charts/web", deploymentResult.Configurations[0].RenderedFrom)
+
+	summary := policyCheckResultData.FormattedResults.EvaluationResults.Summary
+	assert.Equal(t, 2, summary.FilesCount)
+	assert.Equal(t, 0, summary.FilesPassedCount)
+}
+
+func TestEvaluateRulesSeverity(t *testing.T) {
+	missingLabelsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{"required": []interface{}{"labels"}},
+		},
+	}
+	rules := []policy_factory.RuleWithSchema{
+		{RuleIdentifier: "HIGH_MISSING_LABELS", RuleName: "high", Schema: missingLabelsSchema, MessageOnFailure: "Add labels", Severity: policy_factory.SeverityHigh},
+		{RuleIdentifier: "LOW_MISSING_LABELS", RuleName: "low", Schema: missingLabelsSchema, MessageOnFailure: "Add labels", Severity: policy_factory.SeverityLow},
+		{RuleIdentifier: "MISSING_LABELS", RuleName: "no severity", Schema: missingLabelsSchema, MessageOnFailure: "Add labels"},
+	}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/ruleSelectorConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: rules},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := policyCheckResultData.FormattedResults.EvaluationResults.Summary
+	assert.Equal(t, 3, summary.TotalFailedRules)
+	assert.Equal(t, map[string]int{"high": 1, "medium": 1, "low": 1}, summary.TotalFailedRulesBySeverity)
+	assert.Equal(t, "3 (high: 1, medium: 1, low: 1)", getFailedRulesText(summary.TotalFailedRules, summary.TotalFailedRulesBySeverity))
+
+	nonInteractiveResults := policyCheckResultData.FormattedResults.NonInteractiveEvaluationResults
+	assert.Equal(t, &FailedRulesBySeverity{High: 1, Medium: 1, Low: 1}, nonInteractiveResults.PolicySummary.TotalRulesFailedBySeverity)
+
+	ruleResults := nonInteractiveResults.FormattedEvaluationResults[0].RuleResults
+	assert.Equal(t, "high", ruleResults[0].Severity)
+	assert.Equal(t, "error", getSarifLevel(ruleResults[0]))
+	assert.Equal(t, "low", ruleResults[1].Severity)
+	assert.Equal(t, "warning", getSarifLevel(ruleResults[1]))
+	assert.Equal(t, "error", getSarifLevel(ruleResults[2]))
+}
+
+func TestEvaluateWithBaseline(t *testing.T) {
+	missingLabelsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{"required": []interface{}{"labels"}},
+		},
+	}
+	policy := policy_factory.Policy{Name: "Default", Rules: []policy_factory.RuleWithSchema{
+		{RuleIdentifier: "MISSING_LABELS", RuleName: "Ensure labels are set", Schema: missingLabelsSchema, MessageOnFailure: "Add labels"},
+	}}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/ruleSelectorConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+	filesConfigurations := []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{FilesConfigurations: filesConfigurations, Policy: policy, WriteBaseline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baselineResources []string
+	for _, entry := range policyCheckResultData.NewBaseline.Entries {
+		baselineResources = append(baselineResources, entry.Namespace+"/"+entry.Name)
+	}
+	assert.ElementsMatch(t, []string{"prod/web", "kube-system/coredns", "prod/settings"}, baselineResources)
+
+	policyCheckResultData, err = evaluator.Evaluate(PolicyCheckData{FilesConfigurations: filesConfigurations, Policy: policy, Baseline: policyCheckResultData.NewBaseline})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, policyCheckResultData.NewBaseline)
+	assert.Equal(t, 0, policyCheckResultData.FormattedResults.EvaluationResults.Summary.TotalFailedRules)
+	assert.Equal(t, 1, policyCheckResultData.FormattedResults.EvaluationResults.Summary.FilesPassedCount)
+	assert.Equal(t, &baseline.Result{HiddenCount: 3, FixedEntries: []baseline.Entry{}}, policyCheckResultData.FormattedResults.BaselineResult)
+}
+
+func TestEvaluateWithExceptions(t *testing.T) {
+	missingLabelsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{"required": []interface{}{"labels"}},
+		},
+	}
+	policy := policy_factory.Policy{Name: "Default", Rules: []policy_factory.RuleWithSchema{
+		{RuleIdentifier: "MISSING_LABELS", RuleName: "Ensure labels are set", Schema: missingLabelsSchema, MessageOnFailure: "Add labels"},
+	}}
+	policyExceptions := &exceptions.Exceptions{ApiVersion: "v1", Exceptions: []exceptions.Exception{
+		{Rule: "MISSING_LABELS", Kind: "Deployment", Namespace: "kube-*", Owner: "platform-team", Ticket: "OPS-1", Reason: "managed by the provider"},
+		{Rule: "*", Name: "settings", Owner: "web-team", Reason: "expired", Expires: "2020-01-01"},
+	}}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/ruleSelectorConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy,
+		Exceptions:          policyExceptions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skippedByName := make(map[string]bool)
+	for _, configuration := range policyCheckResultData.RawResults[absolutePath]["MISSING_LABELS"].Configurations {
+		skippedByName[configuration.Name] = configuration.IsSkipped
+		if configuration.Name == "coredns" {
+			assert.Equal(t, &policyExceptions.Exceptions[0], configuration.Exception)
+			assert.Equal(t, "managed by the provider (owner: platform-team, ticket: OPS-1)", configuration.SkipMessage)
+		}
+	}
+	// the expired exception doesn't skip the settings failure
+	assert.Equal(t, map[string]bool{"web": false, "coredns": true, "settings": false}, skippedByName)
+}
+
+// each benchmark iteration simulates a full run over a large set of manifests, starting from an empty schemas cache
+const benchmarkConfigurationsCopies = 10
+
+func BenchmarkEvaluate(b *testing.B) {
+	policyCheckData := newBenchmarkPolicyCheckData()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluator := New(&mockCliClient{}, nil)
+		_, err := evaluator.Evaluate(policyCheckData)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateWithCompiledPolicy(b *testing.B) {
+	policyCheckData := newBenchmarkPolicyCheckData()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		jsonSchemaValidator := jsonSchemaValidator.New()
+		compiledPolicy, err := policy_factory.CompilePolicy(policyCheckData.Policy, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, filesConfiguration := range policyCheckData.FilesConfigurations {
+			for _, configuration := range filesConfiguration.Configurations {
+				var configurationJson interface{}
+				if err := json.Unmarshal(configuration.Payload, &configurationJson); err != nil {
+					b.Fatal(err)
+				}
+				for _, rule := range compiledPolicy.Rules {
+					_, err := jsonSchemaValidator.ValidateCompiledSchema(compiledPolicy.Schemas[rule.RuleIdentifier], configurationJson)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkValidatePerRule measures the previous flow, in which every rule schema was marshalled, hashed and
+// looked up in the validator cache for every configuration, to compare against BenchmarkValidateWithCompiledPolicy
+func BenchmarkValidatePerRule(b *testing.B) {
+	policyCheckData := newBenchmarkPolicyCheckData()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		jsonSchemaValidator := jsonSchemaValidator.New()
+		for _, filesConfiguration := range policyCheckData.FilesConfigurations {
+			for _, configuration := range filesConfiguration.Configurations {
+				for _, rule := range policyCheckData.Policy.Rules {
+					ruleSchemaJson, err := json.Marshal(rule.Schema)
+					if err != nil {
+						b.Fatal(err)
+					}
+					_, err = jsonSchemaValidator.ValidateYamlSchema(string(ruleSchemaJson), string(configuration.Payload))
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func newBenchmarkPolicyCheckData() PolicyCheckData {
+	defaultRules, err := defaultRules.GetDefaultRules()
+	if err != nil {
+		panic(err)
+	}
+
+	policy, err := policy_factory.CreatePolicy(defaultPolicies.GetDefaultPoliciesStruct(), "", "", defaultRules, false)
+	if err != nil {
+		panic(err)
+	}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("../../internal/fixtures/kube/k8s-demo.yaml")
+	if invalidFile != nil {
+		panic(invalidFile.ValidationErrors[0])
+	}
+
+	var filesConfigurations []*extractor.FileConfigurations
+	for i := 0; i < benchmarkConfigurationsCopies; i++ {
+		filesConfigurations = append(filesConfigurations, &extractor.FileConfigurations{FileName: fmt.Sprintf("%s_%d", absolutePath, i), Configurations: *configurations})
+	}
+
+	return PolicyCheckData{
+		FilesConfigurations: filesConfigurations,
+		PolicyName:          policy.Name,
+		Policy:              policy,
+	}
 }
 
 type evaluateArgs struct {
@@ -263,11 +760,12 @@ func request_evaluation_all_valid() *evaluateTestCase {
 					EvaluationResults: &EvaluationResults{
 						FileNameRuleMapper: make(map[string]map[string]*Rule),
 						Summary: EvaluationResultsSummery{
-							TotalFailedRules:  0,
-							TotalSkippedRules: 0,
-							TotalPassedRules:  3,
-							FilesCount:        1,
-							FilesPassedCount:  1,
+							TotalFailedRules:           0,
+							TotalSkippedRules:          0,
+							TotalPassedRules:           3,
+							FilesCount:                 1,
+							FilesPassedCount:           1,
+							TotalFailedRulesBySeverity: map[string]int{},
 						},
 					},
 					NonInteractiveEvaluationResults: nil,
@@ -0,0 +1,96 @@
+package evaluation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// inline ignores are yaml comments such as "# datree:ignore RULE_ID reason".
+// A comment on a key ignores the failures on that key or under it, a comment at the top of the document ignores the rule for the whole resource
+const INLINE_IGNORE_PREFIX string = "datree:ignore"
+
+// extractInlineIgnores returns the inline ignore comments of a yaml document as skip annotations scoped to the commented node
+func extractInlineIgnores(documentNode yaml.Node) []*skipAnnotation {
+	var inlineIgnores []*skipAnnotation
+
+	rootNode := &documentNode
+	documentComments := []string{documentNode.HeadComment}
+	if documentNode.Kind == yaml.DocumentNode && len(documentNode.Content) > 0 {
+		rootNode = documentNode.Content[0]
+		documentComments = append(documentComments, rootNode.HeadComment)
+		// a comment right above the first key is attached to the key rather than to the document
+		if rootNode.Kind == yaml.MappingNode && len(rootNode.Content) > 0 {
+			documentComments = append(documentComments, rootNode.Content[0].HeadComment)
+		}
+	}
+
+	for _, comment := range documentComments {
+		inlineIgnores = append(inlineIgnores, parseInlineIgnores(comment, "")...)
+	}
+
+	var walk func(node *yaml.Node, schemaPath string)
+	walk = func(node *yaml.Node, schemaPath string) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for index := 0; index+1 < len(node.Content); index += 2 {
+				keyNode, valueNode := node.Content[index], node.Content[index+1]
+				valueSchemaPath := schemaPath + "/" + escapeJsonPointer(keyNode.Value)
+				keyComments := []string{keyNode.LineComment}
+				// the head comment of the first key of the document was already used as a document comment
+				if !(node == rootNode && index == 0) {
+					keyComments = append(keyComments, keyNode.HeadComment)
+				}
+				for _, comment := range keyComments {
+					inlineIgnores = append(inlineIgnores, parseInlineIgnores(comment, valueSchemaPath)...)
+				}
+				walk(valueNode, valueSchemaPath)
+			}
+		case yaml.SequenceNode:
+			for index, itemNode := range node.Content {
+				itemSchemaPath := schemaPath + "/" + strconv.Itoa(index)
+				inlineIgnores = append(inlineIgnores, parseInlineIgnores(itemNode.HeadComment, itemSchemaPath)...)
+				walk(itemNode, itemSchemaPath)
+			}
+		case yaml.ScalarNode:
+			inlineIgnores = append(inlineIgnores, parseInlineIgnores(node.LineComment, schemaPath)...)
+		}
+	}
+	walk(rootNode, "")
+
+	return inlineIgnores
+}
+
+// parseInlineIgnores returns a skip annotation for each inline ignore line of a comment
+func parseInlineIgnores(comment string, schemaPath string) []*skipAnnotation {
+	var inlineIgnores []*skipAnnotation
+	for _, commentLine := range strings.Split(comment, "\n") {
+		commentText := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(commentLine), "#"))
+		fields := strings.Fields(commentText)
+		if len(fields) == 0 || fields[0] != INLINE_IGNORE_PREFIX {
+			continue
+		}
+
+		location := "at " + schemaPath
+		if schemaPath == "" {
+			location = "at the top of the document"
+		}
+
+		inlineIgnore := &skipAnnotation{message: commentText, schemaPath: schemaPath}
+		if len(fields) < 2 {
+			inlineIgnore.key = fmt.Sprintf("%s comment %s", INLINE_IGNORE_PREFIX, location)
+			inlineIgnore.problem = inlineIgnore.key + " is missing a rule identifier"
+		} else {
+			inlineIgnore.target = fields[1]
+			inlineIgnore.key = fmt.Sprintf("%s %s comment %s", INLINE_IGNORE_PREFIX, fields[1], location)
+		}
+		inlineIgnores = append(inlineIgnores, inlineIgnore)
+	}
+	return inlineIgnores
+}
+
+func escapeJsonPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
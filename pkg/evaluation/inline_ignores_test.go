@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"testing"
+
+	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/extractor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractInlineIgnores(t *testing.T) {
+	configurations, err := extractor.ParseYaml(`apiVersion: v1
+kind: Pod
+metadata:
+  name: web # datree:ignore NAME_RULE the name is set by the tool
+  labels:
+    # datree:ignore LABELS_RULE
+    app.kubernetes.io/name: web
+spec:
+  # not an ignore comment
+  containers: # datree:ignore
+    - name: app
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inlineIgnores := extractInlineIgnores((*configurations)[0].YamlNode)
+	assert.Equal(t, 3, len(inlineIgnores))
+
+	assert.Equal(t, "NAME_RULE", inlineIgnores[0].target)
+	assert.Equal(t, "/metadata/name", inlineIgnores[0].schemaPath)
+	assert.Equal(t, "datree:ignore NAME_RULE the name is set by the tool", inlineIgnores[0].message)
+
+	assert.Equal(t, "LABELS_RULE", inlineIgnores[1].target)
+	assert.Equal(t, "/metadata/labels/app.kubernetes.io~1name", inlineIgnores[1].schemaPath)
+
+	assert.Equal(t, "datree:ignore comment at /spec/containers is missing a rule identifier", inlineIgnores[2].problem)
+}
+
+func TestEvaluateWithInlineIgnores(t *testing.T) {
+	missingLabelsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"metadata": map[string]interface{}{"required": []interface{}{"labels"}},
+		},
+	}
+	missingLimitsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"template": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"properties": map[string]interface{}{
+									"containers": map[string]interface{}{
+										"items": map[string]interface{}{"required": []interface{}{"resources"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	rules := []policy_factory.RuleWithSchema{
+		{RuleIdentifier: "WORKLOAD_MISSING_LABELS", RuleName: "Ensure workloads have labels", Schema: missingLabelsSchema, MessageOnFailure: "Add labels"},
+		{RuleIdentifier: "CONTAINERS_MISSING_LIMITS", RuleName: "Ensure containers have limits", Schema: missingLimitsSchema, MessageOnFailure: "Add limits"},
+	}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/inlineIgnoresConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: rules},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getConfigurationsByName := func(ruleIdentifier string) map[string][]cliClient.Configuration {
+		configurationsByName := make(map[string][]cliClient.Configuration)
+		for _, configuration := range policyCheckResultData.RawResults[absolutePath][ruleIdentifier].Configurations {
+			configurationsByName[configuration.Name] = append(configurationsByName[configuration.Name], configuration)
+		}
+		return configurationsByName
+	}
+
+	// the comment at the top of the document ignores the rule for the whole resource
+	labelsConfigurations := getConfigurationsByName("WORKLOAD_MISSING_LABELS")
+	assert.True(t, labelsConfigurations["web"][0].IsSkipped)
+	assert.Equal(t, "datree:ignore WORKLOAD_MISSING_LABELS generated by a tool that drops labels", labelsConfigurations["web"][0].SkipMessage)
+	assert.False(t, labelsConfigurations["worker"][0].IsSkipped)
+
+	limitsConfigurations := getConfigurationsByName("CONTAINERS_MISSING_LIMITS")
+	assert.Equal(t, 2, len(limitsConfigurations["web"]))
+	for _, configuration := range limitsConfigurations["web"] {
+		if configuration.IsSkipped {
+			assert.Equal(t, "/spec/template/spec/containers/1", configuration.FailureLocations[0].SchemaPath)
+		} else {
+			assert.Equal(t, "/spec/template/spec/containers/0", configuration.FailureLocations[0].SchemaPath)
+		}
+	}
+	assert.Equal(t, 1, len(limitsConfigurations["worker"]))
+	assert.True(t, limitsConfigurations["worker"][0].IsSkipped)
+	assert.Equal(t, 2, limitsConfigurations["worker"][0].Occurrences)
+
+	skipAnnotationsWarning := policyCheckResultData.RawResults[absolutePath][SkipAnnotationsRuleIdentifier]
+	assert.Equal(t, 1, len(skipAnnotationsWarning.Configurations))
+	assert.Equal(t, []string{"datree:ignore UNRELATED_RULE comment at /spec/template/spec/hostNetwork is unused, it didn't skip any failure"}, skipAnnotationsWarning.Configurations[0].ValidationFailureMessages)
+}
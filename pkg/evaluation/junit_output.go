@@ -3,7 +3,9 @@ package evaluation
 import (
 	"encoding/xml"
 	"strconv"
+	"strings"
 
+	policy_factory "github.com/datreeio/datree/bl/policy"
 	"github.com/datreeio/datree/pkg/cliClient"
 	"github.com/datreeio/datree/pkg/utils"
 )
@@ -38,6 +40,8 @@ type testCase struct {
 	Skipped          *skipped
 	Failure          *failure
 	DocumentationUrl *documentationUrl
+	SystemOut        *systemOut
+	Properties       *[]property `xml:"properties>property,omitempty"`
 }
 
 type skipped struct {
@@ -56,12 +60,22 @@ type failure struct {
 	Content string   `xml:",chardata"`
 }
 
+// systemOut holds the warnings of a test case, JUnit has no warning element and a failure would fail the build
+type systemOut struct {
+	XMLName xml.Name `xml:"system-out,omitempty"`
+	Content string   `xml:",chardata"`
+}
+
 type AdditionalJUnitData struct {
 	AllEnabledRules            []cliClient.RuleData
 	AllFilesThatRanPolicyCheck []string
 }
 
 func FormattedOutputToJUnitOutput(formattedOutput FormattedOutput, additionalJUnitData AdditionalJUnitData, verbose bool) JUnitOutput {
+	if len(formattedOutput.Policies) > 0 {
+		return policiesToJUnitOutput(formattedOutput, verbose)
+	}
+
 	var jUnitOutput JUnitOutput
 
 	if formattedOutput.PolicySummary != nil {
@@ -86,25 +100,72 @@ func FormattedOutputToJUnitOutput(formattedOutput FormattedOutput, additionalJUn
 		jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getInvalidK8sFilesTestSuite(formattedOutput)...)
 	}
 
-	for _, fileThatRanPolicyCheck := range additionalJUnitData.AllFilesThatRanPolicyCheck {
-		policyValidationResult := findFileInPolicyValidationResults(fileThatRanPolicyCheck, formattedOutput.PolicyValidationResults)
+	jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getPolicyCheckTestSuites(formattedOutput.PolicyValidationResults, additionalJUnitData, verbose)...)
 
-		if policyValidationResult != nil {
-			jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getPolicyValidationResultTestSuite(policyValidationResult, additionalJUnitData.AllEnabledRules, verbose))
-		} else {
-			jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getPassingFileTestSuite(fileThatRanPolicyCheck, additionalJUnitData.AllEnabledRules))
-		}
+	if formattedOutput.PolicySummary != nil {
+		jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getPolicySummaryTestSuite(formattedOutput.PolicySummary))
 	}
 
-	if formattedOutput.PolicySummary != nil {
-		jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getPolicySummaryTestSuite(formattedOutput))
+	jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getEvaluationSummaryTestSuite(formattedOutput))
+
+	return jUnitOutput
+}
+
+// policiesToJUnitOutput returns the test suites of each policy of a run that evaluates several policies,
+// the names of the suites of a policy are prefixed with the policy name
+func policiesToJUnitOutput(formattedOutput FormattedOutput, verbose bool) JUnitOutput {
+	jUnitOutput := JUnitOutput{TestSuites: []testSuite{}}
+
+	if len(formattedOutput.YamlValidationResults) > 0 {
+		jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getInvalidYamlFilesTestSuite(formattedOutput)...)
+	}
+
+	if len(formattedOutput.K8sValidationResults) > 0 {
+		jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getInvalidK8sFilesTestSuite(formattedOutput)...)
+	}
+
+	var policiesNames []string
+	for _, policyOutput := range formattedOutput.Policies {
+		policiesNames = append(policiesNames, policyOutput.PolicyName)
+
+		policySuites := getPolicyCheckTestSuites(policyOutput.PolicyValidationResults, policyOutput.additionalJUnitData, verbose)
+		if policyOutput.PolicySummary != nil {
+			jUnitOutput.Tests += policyOutput.PolicySummary.TotalRulesInPolicy
+			jUnitOutput.Failures += policyOutput.PolicySummary.TotalRulesFailed
+			jUnitOutput.Skipped += policyOutput.PolicySummary.TotalSkippedRules
+			policySuites = append(policySuites, getPolicySummaryTestSuite(policyOutput.PolicySummary))
+		}
+
+		for _, suite := range policySuites {
+			suite.Name = policyOutput.PolicyName + "/" + suite.Name
+			if suite.Properties == nil {
+				suite.Properties = &[]property{}
+			}
+			*suite.Properties = append(*suite.Properties, property{Name: "policy", Value: policyOutput.PolicyName}, property{Name: "isGating", Value: strconv.FormatBool(policyOutput.IsGating)})
+			jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, suite)
+		}
 	}
+	jUnitOutput.Name = strings.Join(policiesNames, ",")
 
 	jUnitOutput.TestSuites = append(jUnitOutput.TestSuites, getEvaluationSummaryTestSuite(formattedOutput))
 
 	return jUnitOutput
 }
 
+func getPolicyCheckTestSuites(policyValidationResults []*FormattedEvaluationResults, additionalJUnitData AdditionalJUnitData, verbose bool) []testSuite {
+	var suites []testSuite
+	for _, fileThatRanPolicyCheck := range additionalJUnitData.AllFilesThatRanPolicyCheck {
+		policyValidationResult := findFileInPolicyValidationResults(fileThatRanPolicyCheck, policyValidationResults)
+
+		if policyValidationResult != nil {
+			suites = append(suites, getPolicyValidationResultTestSuite(policyValidationResult, additionalJUnitData.AllEnabledRules, verbose))
+		} else {
+			suites = append(suites, getPassingFileTestSuite(fileThatRanPolicyCheck, additionalJUnitData.AllEnabledRules))
+		}
+	}
+	return suites
+}
+
 func getPassingFileTestSuite(fileName string, allEnabledRules []cliClient.RuleData) testSuite {
 	return testSuite{
 		Name: fileName,
@@ -133,12 +194,20 @@ func getPolicyValidationResultTestSuite(policyValidationResult *FormattedEvaluat
 
 		ruleResult := findRuleResult(rule, policyValidationResult.RuleResults)
 
-		if ruleResult != nil {
+		if ruleResult != nil && ruleResult.IsWarning {
+			testCase.SystemOut = &systemOut{
+				Content: "warning: " + ruleResult.MessageOnFailure + "\n" + getContentFromOccurrencesDetails(ruleResult.OccurrencesDetails),
+			}
+		} else if ruleResult != nil {
 			testCase.Failure = &failure{
 				Message: ruleResult.MessageOnFailure,
 				Content: getContentFromOccurrencesDetails(ruleResult.OccurrencesDetails),
 			}
 
+			if ruleResult.Severity != "" {
+				testCase.Properties = &[]property{{Name: "severity", Value: ruleResult.Severity}}
+			}
+
 			if verbose {
 				testCase.DocumentationUrl = &documentationUrl{
 					Message: ruleResult.DocumentationUrl,
@@ -164,26 +233,45 @@ func findRuleResult(ruleData cliClient.RuleData, ruleResults []*RuleResult) *Rul
 	return nil
 }
 
-func getPolicySummaryTestSuite(formattedOutput FormattedOutput) testSuite {
-	return testSuite{
+func getPolicySummaryTestSuite(policySummary *PolicySummary) testSuite {
+	suite := testSuite{
 		Name: "policySummary",
 		Properties: &[]property{{
 			Name:  "policyName",
-			Value: formattedOutput.PolicySummary.PolicyName,
+			Value: policySummary.PolicyName,
 		}, {
 			Name:  "totalRulesInPolicy",
-			Value: strconv.Itoa(formattedOutput.PolicySummary.TotalRulesInPolicy),
+			Value: strconv.Itoa(policySummary.TotalRulesInPolicy),
 		}, {
 			Name:  "totalSkippedRules",
-			Value: strconv.Itoa(formattedOutput.PolicySummary.TotalSkippedRules),
+			Value: strconv.Itoa(policySummary.TotalSkippedRules),
 		}, {
 			Name:  "totalRulesFailed",
-			Value: strconv.Itoa(formattedOutput.PolicySummary.TotalRulesFailed),
+			Value: strconv.Itoa(policySummary.TotalRulesFailed),
 		}, {
 			Name:  "totalPassedCount",
-			Value: strconv.Itoa(formattedOutput.PolicySummary.TotalPassedCount),
+			Value: strconv.Itoa(policySummary.TotalPassedCount),
 		}},
 	}
+
+	if policySummary.TotalWarningRules > 0 {
+		*suite.Properties = append(*suite.Properties, property{
+			Name:  "totalWarningRules",
+			Value: strconv.Itoa(policySummary.TotalWarningRules),
+		})
+	}
+
+	if failedRulesBySeverity := policySummary.TotalRulesFailedBySeverity; failedRulesBySeverity != nil {
+		for _, severity := range policy_factory.Severities {
+			if count := failedRulesBySeverity.Get(severity); count > 0 {
+				*suite.Properties = append(*suite.Properties, property{
+					Name:  "totalRulesFailed." + severity,
+					Value: strconv.Itoa(count),
+				})
+			}
+		}
+	}
+	return suite
 }
 
 func getEvaluationSummaryTestSuite(formattedOutput FormattedOutput) testSuite {
@@ -215,7 +303,14 @@ func getContentFromOccurrencesDetails(occurrencesDetails []OccurrenceDetails) st
 	var skipLines string
 
 	for _, occurrenceDetails := range occurrencesDetails {
-		currentLine := "- metadata.name: " + occurrenceDetails.MetadataName + " (kind: " + occurrenceDetails.Kind + ")\n"
+		resourceDetails := "kind: " + occurrenceDetails.Kind
+		if occurrenceDetails.DocumentIndex != nil {
+			resourceDetails += ", document: " + strconv.Itoa(*occurrenceDetails.DocumentIndex)
+		}
+		if occurrenceDetails.RenderedFrom != "" {
+			resourceDetails += ", rendered from: " + occurrenceDetails.RenderedFrom
+		}
+		currentLine := "- metadata.name: " + occurrenceDetails.MetadataName + " (" + resourceDetails + ")\n"
 
 		totalOccurrences += occurrenceDetails.Occurrences
 		occurrencesLines += currentLine
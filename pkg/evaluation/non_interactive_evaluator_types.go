@@ -1,6 +1,8 @@
 package evaluation
 
 import (
+	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/datreeio/datree/pkg/baseline"
 	"github.com/datreeio/datree/pkg/extractor"
 )
 
@@ -11,6 +13,18 @@ type FormattedOutput struct {
 	YamlValidationResults   []*extractor.InvalidFile        `yaml:"yamlValidationResults" json:"yamlValidationResults" xml:"yamlValidationResults"`
 	K8sValidationResults    []*extractor.InvalidFile        `yaml:"k8sValidationResults" json:"k8sValidationResults" xml:"k8sValidationResults"`
 	LoginUrl                string                          `yaml:"loginUrl" json:"loginUrl" xml:"loginUrl"`
+	Baseline                *baseline.Result                `yaml:"baseline,omitempty" json:"baseline,omitempty" xml:"baseline,omitempty"`
+	// Policies is set when several policies are evaluated, the policy validation results and policy summary above are of the gating policy
+	Policies []*PolicyOutput `yaml:"policies,omitempty" json:"policies,omitempty" xml:"policy,omitempty"`
+}
+
+// PolicyOutput is the output of one of the policies of a run that evaluates several policies
+type PolicyOutput struct {
+	PolicyName              string                        `yaml:"policyName" json:"policyName" xml:"policyName"`
+	IsGating                bool                          `yaml:"isGating" json:"isGating" xml:"isGating"`
+	PolicyValidationResults []*FormattedEvaluationResults `yaml:"policyValidationResults" json:"policyValidationResults" xml:"policyValidationResults"`
+	PolicySummary           *PolicySummary                `yaml:"policySummary" json:"policySummary" xml:"policySummary"`
+	additionalJUnitData     AdditionalJUnitData
 }
 
 type NonInteractiveEvaluationResults struct {
@@ -29,6 +43,8 @@ type RuleResult struct {
 	MessageOnFailure   string              `yaml:"messageOnFailure" json:"messageOnFailure" xml:"messageOnFailure"`
 	OccurrencesDetails []OccurrenceDetails `yaml:"occurrencesDetails" json:"occurrencesDetails" xml:"occurrencesDetails"`
 	DocumentationUrl   string              `yaml:"documentationUrl,omitempty" json:"documentationUrl,omitempty" xml:"documentationUrl,omitempty"`
+	IsWarning          bool                `yaml:"isWarning,omitempty" json:"isWarning,omitempty" xml:"isWarning,omitempty"`
+	Severity           string              `yaml:"severity,omitempty" json:"severity,omitempty" xml:"severity,omitempty"`
 }
 
 type NonInteractiveEvaluationSummary struct {
@@ -37,6 +53,8 @@ type NonInteractiveEvaluationSummary struct {
 	PassedYamlValidationCount   int    `yaml:"passedYamlValidationCount" json:"passedYamlValidationCount" xml:"passedYamlValidationCount"`
 	K8sValidation               string `yaml:"k8sValidation" json:"k8sValidation" xml:"k8sValidation"`
 	PassedPolicyValidationCount int    `yaml:"passedPolicyValidationCount" json:"passedPolicyValidationCount" xml:"passedPolicyValidationCount"`
+	// PolicyValidationFilesCount is set when rendered files were evaluated as the files of their sources
+	PolicyValidationFilesCount int `yaml:"policyValidationFilesCount,omitempty" json:"policyValidationFilesCount,omitempty" xml:"policyValidationFilesCount,omitempty"`
 }
 
 type PolicySummary struct {
@@ -45,4 +63,42 @@ type PolicySummary struct {
 	TotalSkippedRules  int    `yaml:"totalSkippedRules" json:"totalSkippedRules" xml:"totalSkippedRules"`
 	TotalRulesFailed   int    `yaml:"totalRulesFailed"  json:"totalRulesFailed" xml:"totalRulesFailed"`
 	TotalPassedCount   int    `yaml:"totalPassedCount"  json:"totalPassedCount" xml:"totalPassedCount"`
+	TotalWarningRules  int    `yaml:"totalWarningRules,omitempty" json:"totalWarningRules,omitempty" xml:"totalWarningRules,omitempty"`
+	// the struct is used instead of a map since maps can't be marshaled to xml
+	TotalRulesFailedBySeverity *FailedRulesBySeverity `yaml:"totalRulesFailedBySeverity,omitempty" json:"totalRulesFailedBySeverity,omitempty" xml:"totalRulesFailedBySeverity,omitempty"`
+}
+
+type FailedRulesBySeverity struct {
+	Critical int `yaml:"critical" json:"critical" xml:"critical"`
+	High     int `yaml:"high" json:"high" xml:"high"`
+	Medium   int `yaml:"medium" json:"medium" xml:"medium"`
+	Low      int `yaml:"low" json:"low" xml:"low"`
+	Info     int `yaml:"info" json:"info" xml:"info"`
+}
+
+func (failedRulesBySeverity *FailedRulesBySeverity) Get(severity string) int {
+	switch severity {
+	case policy_factory.SeverityCritical:
+		return failedRulesBySeverity.Critical
+	case policy_factory.SeverityHigh:
+		return failedRulesBySeverity.High
+	case policy_factory.SeverityMedium:
+		return failedRulesBySeverity.Medium
+	case policy_factory.SeverityLow:
+		return failedRulesBySeverity.Low
+	case policy_factory.SeverityInfo:
+		return failedRulesBySeverity.Info
+	default:
+		return 0
+	}
+}
+
+func newFailedRulesBySeverity(failedRulesBySeverity map[string]int) *FailedRulesBySeverity {
+	return &FailedRulesBySeverity{
+		Critical: failedRulesBySeverity[policy_factory.SeverityCritical],
+		High:     failedRulesBySeverity[policy_factory.SeverityHigh],
+		Medium:   failedRulesBySeverity[policy_factory.SeverityMedium],
+		Low:      failedRulesBySeverity[policy_factory.SeverityLow],
+		Info:     failedRulesBySeverity[policy_factory.SeverityInfo],
+	}
 }
@@ -10,7 +10,9 @@ import (
 	"sort"
 	"strings"
 
+	policy_factory "github.com/datreeio/datree/bl/policy"
 	"github.com/datreeio/datree/bl/validation"
+	"github.com/datreeio/datree/pkg/baseline"
 	"github.com/datreeio/datree/pkg/extractor"
 	"github.com/fatih/color"
 	"github.com/owenrumney/go-sarif/v2/sarif"
@@ -43,6 +45,17 @@ type PrintResultsData struct {
 	CliVersion            string
 	IsCI                  bool
 	Quiet                 bool
+	// PoliciesResults is set when several policies are evaluated, Results and PolicyName are of the gating policy
+	PoliciesResults []PolicyResultsData
+}
+
+// PolicyResultsData is the results of one of the policies of a run that evaluates several policies
+type PolicyResultsData struct {
+	PolicyName          string
+	IsGating            bool
+	RulesCount          int
+	Results             FormattedResults
+	AdditionalJUnitData AdditionalJUnitData
 }
 
 type textOutputData struct {
@@ -57,6 +70,8 @@ type textOutputData struct {
 	policyName            string
 	k8sValidationWarnings validation.K8sValidationWarningPerValidFile
 	quiet                 bool
+	baselineResult        *baseline.Result
+	policiesResults       []PolicyResultsData
 }
 
 func SaveLastResultToJson(resultsData *PrintResultsData) {
@@ -120,6 +135,24 @@ func getFormattedOutput(resultsData *PrintResultsData) FormattedOutput {
 		YamlValidationResults: resultsData.InvalidYamlFiles,
 		K8sValidationResults:  resultsData.InvalidK8sFiles,
 		LoginUrl:              resultsData.LoginURL,
+		Baseline:              resultsData.Results.BaselineResult,
+	}
+
+	if resultsData.EvaluationSummary.PolicyCheckFilesCount != resultsData.EvaluationSummary.FilesCount {
+		formattedOutput.EvaluationSummary.PolicyValidationFilesCount = resultsData.EvaluationSummary.PolicyCheckFilesCount
+	}
+
+	for _, policyResults := range resultsData.PoliciesResults {
+		policyOutput := &PolicyOutput{
+			PolicyName:          policyResults.PolicyName,
+			IsGating:            policyResults.IsGating,
+			additionalJUnitData: policyResults.AdditionalJUnitData,
+		}
+		if policyResults.Results.NonInteractiveEvaluationResults != nil {
+			policyOutput.PolicyValidationResults = policyResults.Results.NonInteractiveEvaluationResults.FormattedEvaluationResults
+			policyOutput.PolicySummary = policyResults.Results.NonInteractiveEvaluationResults.PolicySummary
+		}
+		formattedOutput.Policies = append(formattedOutput.Policies, policyOutput)
 	}
 	return formattedOutput
 }
@@ -155,6 +188,8 @@ func GetResultsText(resultsData *PrintResultsData) (string, error) {
 			Verbose:               resultsData.Verbose,
 			k8sValidationWarnings: resultsData.K8sValidationWarnings,
 			quiet:                 resultsData.Quiet,
+			baselineResult:        resultsData.Results.BaselineResult,
+			policiesResults:       resultsData.PoliciesResults,
 		})
 	}
 }
@@ -198,13 +233,34 @@ func getSarifOutput(formattedOutput *FormattedOutput, cliVersion string) (string
 		return "", err
 	}
 
+	if len(formattedOutput.Policies) == 0 {
+		report.AddRun(getSarifRun(formattedOutput.PolicyValidationResults, cliVersion))
+	}
+
+	// each policy is a run with its own category, so code scanning tools keep the results of the policies apart
+	for _, policyOutput := range formattedOutput.Policies {
+		run := getSarifRun(policyOutput.PolicyValidationResults, cliVersion)
+		run.WithAutomationDetails(sarif.NewRunAutomationDetails().WithID("datree/" + policyOutput.PolicyName + "/"))
+		run.Properties = sarif.Properties{"policy": policyOutput.PolicyName, "isGating": policyOutput.IsGating}
+		report.AddRun(run)
+	}
+
+	marshal, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintln(string(marshal)), nil
+}
+
+func getSarifRun(policyValidationResults []*FormattedEvaluationResults, cliVersion string) *sarif.Run {
 	const repoURL = "https://github.com/datreeio/datree"
 
 	// create a run for datree
 	run := sarif.NewRunWithInformationURI("datree", repoURL)
 	run.Tool.Driver.WithSemanticVersion(cliVersion)
 
-	for _, validationResult := range formattedOutput.PolicyValidationResults {
+	for _, validationResult := range policyValidationResults {
 		githubPrefix := "/github/workspace/"
 		fileName := validationResult.FileName
 		fileName = strings.TrimPrefix(fileName, githubPrefix)
@@ -228,22 +284,42 @@ func getSarifOutput(formattedOutput *FormattedOutput, cliVersion string) (string
 
 					// create a result for each violation
 					result := run.CreateResultForRule(ruleResult.Identifier).WithMessage(sarif.NewTextMessage(ruleResult.MessageOnFailure))
+					result.WithLevel(getSarifLevel(ruleResult))
 
-					result.AddLocation(sarif.NewLocationWithPhysicalLocation(
-						sarif.NewPhysicalLocation().WithArtifactLocation(sarif.NewSimpleArtifactLocation(fileName)).WithRegion(sarif.NewSimpleRegion(failureLocation.FailedErrorLine, failureLocation.FailedErrorLine))))
+					// the lines of a rendered resource aren't the lines of its source, so it's located by its document instead
+					physicalLocation := sarif.NewPhysicalLocation().WithArtifactLocation(sarif.NewSimpleArtifactLocation(fileName))
+					if occurrenceDetails.DocumentIndex == nil {
+						physicalLocation.WithRegion(sarif.NewSimpleRegion(failureLocation.FailedErrorLine, failureLocation.FailedErrorLine))
+					} else {
+						result.Properties = sarif.Properties{"documentIndex": *occurrenceDetails.DocumentIndex, "renderedLine": failureLocation.FailedErrorLine}
+					}
+					if occurrenceDetails.RenderedFrom != "" {
+						if result.Properties == nil {
+							result.Properties = sarif.Properties{}
+						}
+						result.Properties["renderedFrom"] = occurrenceDetails.RenderedFrom
+					}
+					result.AddLocation(sarif.NewLocationWithPhysicalLocation(physicalLocation))
 				}
 			}
 		}
 	}
+	return run
+}
 
-	report.AddRun(run)
-
-	marshal, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		return "", err
+// getSarifLevel maps the severity of a rule to a sarif level, warning rules are always reported as warnings
+func getSarifLevel(ruleResult *RuleResult) string {
+	if ruleResult.IsWarning {
+		return "warning"
+	}
+	switch ruleResult.Severity {
+	case policy_factory.SeverityLow:
+		return "warning"
+	case policy_factory.SeverityInfo:
+		return "note"
+	default:
+		return "error"
 	}
-
-	return fmt.Sprintln(string(marshal)), nil
 }
 
 func convertStructToXml(output interface{}) (string, error) {
@@ -263,6 +339,10 @@ func getTextOutput(outputData textOutputData) (string, error) {
 		return "", err
 	}
 
+	if len(outputData.policiesResults) > 0 {
+		return getPoliciesTextOutput(outputData, pwd)
+	}
+
 	warnings, err := parseToPrinterWarnings(outputData.results, outputData.invalidYamlFiles, outputData.invalidK8sFiles, pwd, outputData.k8sVersion, outputData.k8sValidationWarnings, outputData.Verbose)
 	if err != nil {
 		return "", err
@@ -279,9 +359,62 @@ func getTextOutput(outputData textOutputData) (string, error) {
 	summaryTableText := outputData.printer.GetSummaryTableText(summary)
 	sb.WriteString(summaryTableText)
 
+	if outputData.baselineResult != nil {
+		sb.WriteString(getBaselineResultText(outputData.baselineResult))
+	}
+
 	return sb.String(), nil
 }
 
+// getPoliciesTextOutput groups the policy check results by policy, the invalid files and the evaluation summary are printed once
+func getPoliciesTextOutput(outputData textOutputData, pwd string) (string, error) {
+	sb := strings.Builder{}
+
+	invalidFilesWarnings, err := parseToPrinterWarnings(nil, outputData.invalidYamlFiles, outputData.invalidK8sFiles, pwd, outputData.k8sVersion, outputData.k8sValidationWarnings, outputData.Verbose)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(outputData.printer.GetWarningsText(invalidFilesWarnings, outputData.quiet))
+
+	for _, policyResults := range outputData.policiesResults {
+		sb.WriteString(printer.GetPolicyTitleText(policyResults.PolicyName, policyResults.IsGating))
+
+		warnings, err := parseToPrinterWarnings(policyResults.Results.EvaluationResults, nil, nil, pwd, outputData.k8sVersion, outputData.k8sValidationWarnings, outputData.Verbose)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(outputData.printer.GetWarningsText(warnings, outputData.quiet))
+
+		policyEvaluationSummary := outputData.evaluationSummary
+		policyEvaluationSummary.RulesCount = policyResults.RulesCount
+		summary := parseEvaluationResultsToSummary(policyResults.Results.EvaluationResults, policyEvaluationSummary, outputData.url, policyResults.PolicyName)
+		sb.WriteString(outputData.printer.GetSummaryTableText(summary))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(outputData.printer.GetEvaluationSummaryText(outputData.evaluationSummary, outputData.k8sVersion))
+
+	if outputData.baselineResult != nil {
+		sb.WriteString(getBaselineResultText(outputData.baselineResult))
+	}
+
+	return sb.String(), nil
+}
+
+// getBaselineResultText lists the fixed baseline entries, so they can be pruned from the baseline file
+func getBaselineResultText(baselineResult *baseline.Result) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\nBaseline: %d known violations hidden, %d fixed\n", baselineResult.HiddenCount, len(baselineResult.FixedEntries)))
+	for _, entry := range baselineResult.FixedEntries {
+		sb.WriteString(fmt.Sprintf("- fixed: %s %s (kind: %s) %s", entry.File, entry.Name, entry.Kind, entry.RuleIdentifier))
+		if entry.SchemaPath != "" {
+			sb.WriteString(" at " + entry.SchemaPath)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
 func parseInvalidYamlFilesToWarnings(invalidYamlFiles []*extractor.InvalidFile) []printer.Warning {
 	var warnings []printer.Warning
 
@@ -335,6 +468,7 @@ func parseToPrinterWarnings(results *EvaluationResults, invalidYamlFiles []*extr
 			rules := results.FileNameRuleMapper[filename]
 			var failedRules = []printer.FailedRule{}
 			var skippedRules = []printer.FailedRule{}
+			var warningRules = []printer.FailedRule{}
 
 			rulesUniqueNames := []string{}
 			for rulesUniqueName := range rules {
@@ -357,6 +491,7 @@ func parseToPrinterWarnings(results *EvaluationResults, invalidYamlFiles []*extr
 					Suggestion:         rule.MessageOnFailure,
 					Occurrences:        rule.GetFailedOccurrencesCount(),
 					PACIdentifier:      PACIdentifier,
+					Severity:           rule.Severity,
 					OccurrencesDetails: []printer.OccurrenceDetails{},
 				}
 				skippedRule := failedRule
@@ -368,9 +503,11 @@ func parseToPrinterWarnings(results *EvaluationResults, invalidYamlFiles []*extr
 					if occurrenceDetails.IsSkipped {
 						hasSkippedOccurrences = true
 						skippedRule.OccurrencesDetails = append(skippedRule.OccurrencesDetails, printer.OccurrenceDetails{
-							MetadataName: occurrenceDetails.MetadataName,
-							Kind:         occurrenceDetails.Kind,
-							SkipMessage:  occurrenceDetails.SkipMessage,
+							MetadataName:  occurrenceDetails.MetadataName,
+							Kind:          occurrenceDetails.Kind,
+							SkipMessage:   occurrenceDetails.SkipMessage,
+							DocumentIndex: occurrenceDetails.DocumentIndex,
+							RenderedFrom:  occurrenceDetails.RenderedFrom,
 						})
 					} else {
 						hasFailedOccurrences = true
@@ -381,6 +518,8 @@ func parseToPrinterWarnings(results *EvaluationResults, invalidYamlFiles []*extr
 								Kind:                      occurrenceDetails.Kind,
 								FailureLocations:          occurrenceDetails.FailureLocations,
 								ValidationFailureMessages: occurrenceDetails.ValidationFailureMessages,
+								DocumentIndex:             occurrenceDetails.DocumentIndex,
+								RenderedFrom:              occurrenceDetails.RenderedFrom,
 							},
 						)
 					}
@@ -390,12 +529,15 @@ func parseToPrinterWarnings(results *EvaluationResults, invalidYamlFiles []*extr
 				if hasSkippedOccurrences {
 					skippedRules = append(skippedRules, skippedRule)
 				}
-				if hasFailedOccurrences {
+				if hasFailedOccurrences && rule.IsWarning {
+					warningRules = append(warningRules, failedRule)
+				} else if hasFailedOccurrences {
 					failedRules = append(failedRules, failedRule)
 				}
 			}
 
-			title := filename
+			// relative file names, like the sources of rendered resources, are printed as is
+			title := printer.GetFileNameText(filename)
 			relativePath, _ := filepath.Rel(pwd, filename)
 
 			if relativePath != "" {
@@ -406,6 +548,7 @@ func parseToPrinterWarnings(results *EvaluationResults, invalidYamlFiles []*extr
 				Title:           title,
 				FailedRules:     failedRules,
 				SkippedRules:    skippedRules,
+				WarningRules:    warningRules,
 				InvalidYamlInfo: printer.InvalidYamlInfo{},
 				InvalidK8sInfo: printer.InvalidK8sInfo{
 					ValidationWarning: k8sValidationWarnings[filename].Warning,
@@ -422,12 +565,12 @@ func GetWarningExtraMessages(invalidFile *extractor.InvalidFile) []printer.Extra
 
 	if IsHelmFile(invalidFile.Path) {
 		extraMessages = append(extraMessages, printer.ExtraMessage{
-			Text:  "Are you trying to test a raw Helm file? To run Datree with Helm - check out the helm plugin README:\nhttps://github.com/datreeio/helm-datree \n",
+			Text:  "Are you trying to test a raw Helm file? To run Datree with Helm - use the `--render` flag to render the charts, or check out the helm plugin README:\nhttps://github.com/datreeio/helm-datree \n",
 			Color: "cyan",
 		})
 	} else if IsKustomizationFile(invalidFile.Path) {
 		extraMessages = append(extraMessages, printer.ExtraMessage{
-			Text:  "Are you trying to test Kustomize files? To run Datree with Kustomize, use `datree kustomize test` command, or check out Kustomize support docs:\nhttps://hub.datree.io/kustomize-support \n",
+			Text:  "Are you trying to test Kustomize files? To run Datree with Kustomize, use `datree kustomize test` command or the `--render` flag, or check out Kustomize support docs:\nhttps://hub.datree.io/kustomize-support \n",
 			Color: "cyan",
 		})
 	}
@@ -502,6 +645,7 @@ const (
 	TotalRulesPassed
 	TotalSkippedRules
 	TotalRulesFailed
+	TotalRulesWithWarnings
 )
 
 func (t OutputTitle) String() string {
@@ -511,7 +655,22 @@ func (t OutputTitle) String() string {
 		"See all rules in policy",
 		"Total rules passed",
 		"Total rules skipped",
-		"Total rules failed"}[t]
+		"Total rules failed",
+		"Total rules with warnings"}[t]
+}
+
+// getFailedRulesText breaks the failed rules count down by severity, e.g. "3 (high: 2, low: 1)"
+func getFailedRulesText(totalFailedRules int, totalFailedRulesBySeverity map[string]int) string {
+	var severitiesCounts []string
+	for _, severity := range policy_factory.Severities {
+		if count := totalFailedRulesBySeverity[severity]; count > 0 {
+			severitiesCounts = append(severitiesCounts, fmt.Sprintf("%s: %d", severity, count))
+		}
+	}
+	if len(severitiesCounts) == 0 {
+		return fmt.Sprint(totalFailedRules)
+	}
+	return fmt.Sprintf("%d (%s)", totalFailedRules, strings.Join(severitiesCounts, ", "))
 }
 
 func buildEnabledRulesTitle(policyName string) string {
@@ -526,24 +685,35 @@ func parseEvaluationResultsToSummary(results *EvaluationResults, evaluationSumma
 	totalFailedRules := 0
 	totalSkippedRules := 0
 	totalPassedRules := 0
+	totalWarningRules := 0
+	var totalFailedRulesBySeverity map[string]int
 
 	if results != nil {
 		totalRulesEvaluated = evaluationSummary.RulesCount * results.Summary.FilesCount
 		totalFailedRules = results.Summary.TotalFailedRules
 		totalSkippedRules = results.Summary.TotalSkippedRules
 		totalPassedRules = results.Summary.TotalPassedRules
+		totalWarningRules = results.Summary.TotalWarningRules
+		totalFailedRulesBySeverity = results.Summary.TotalFailedRulesBySeverity
 	}
 
 	plainRows := []printer.SummaryItem{
 		{LeftCol: buildEnabledRulesTitle(policyName), RightCol: fmt.Sprint(evaluationSummary.RulesCount), RowIndex: 0},
 		{LeftCol: EvaluatedConfigurations.String(), RightCol: fmt.Sprint(configsCount), RowIndex: 1},
 		{LeftCol: TotalRulesEvaluated.String(), RightCol: fmt.Sprint(totalRulesEvaluated), RowIndex: 2},
-		{LeftCol: SeeAll.String(), RightCol: loginURL, RowIndex: 6},
 	}
 
+	// the warnings row is shown only when there are warnings, so the summary of policies without warn rules stays the same
+	seeAllRowIndex := 6
+	if totalWarningRules > 0 {
+		plainRows = append(plainRows, printer.SummaryItem{LeftCol: TotalRulesWithWarnings.String(), RightCol: fmt.Sprint(totalWarningRules), RowIndex: seeAllRowIndex})
+		seeAllRowIndex++
+	}
+	plainRows = append(plainRows, printer.SummaryItem{LeftCol: SeeAll.String(), RightCol: loginURL, RowIndex: seeAllRowIndex})
+
 	skipRow := printer.SummaryItem{LeftCol: TotalSkippedRules.String(), RightCol: fmt.Sprint(totalSkippedRules), RowIndex: 3}
 	successRow := printer.SummaryItem{LeftCol: TotalRulesPassed.String(), RightCol: fmt.Sprint(totalPassedRules), RowIndex: 5}
-	errorRow := printer.SummaryItem{LeftCol: TotalRulesFailed.String(), RightCol: fmt.Sprint(totalFailedRules), RowIndex: 4}
+	errorRow := printer.SummaryItem{LeftCol: TotalRulesFailed.String(), RightCol: getFailedRulesText(totalFailedRules, totalFailedRulesBySeverity), RowIndex: 4}
 
 	summary := &printer.Summary{
 		SkipRow:    skipRow,
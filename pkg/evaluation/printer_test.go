@@ -150,6 +150,39 @@ func TestInvalidK8sCustomOutputs(t *testing.T) {
 	assert.Equal(t, expectedOutputs.JUnit, JUnitStdout)
 }
 
+func TestMultiplePoliciesCustomOutputs(t *testing.T) {
+	formattedOutput := createFormattedOutput()
+	additionalJUnitData := createAdditionalJUnitData()
+	formattedOutput.Policies = []*PolicyOutput{{
+		PolicyName:              "Starter",
+		PolicyValidationResults: []*FormattedEvaluationResults{},
+		PolicySummary:           &PolicySummary{PolicyName: "Starter", TotalRulesInPolicy: 5, TotalPassedCount: 5},
+		additionalJUnitData:     additionalJUnitData,
+	}, {
+		PolicyName:              "Default",
+		IsGating:                true,
+		PolicyValidationResults: formattedOutput.PolicyValidationResults,
+		PolicySummary:           formattedOutput.PolicySummary,
+		additionalJUnitData:     additionalJUnitData,
+	}}
+
+	jUnitOutput := FormattedOutputToJUnitOutput(formattedOutput, AdditionalJUnitData{}, false)
+	assert.Equal(t, "Starter,Default", jUnitOutput.Name)
+	assert.Equal(t, 26, jUnitOutput.Tests)
+	assert.Equal(t, 4, jUnitOutput.Failures)
+	var suitesNames []string
+	for _, suite := range jUnitOutput.TestSuites {
+		suitesNames = append(suitesNames, suite.Name)
+	}
+	assert.Equal(t, []string{"Starter/File1", "Starter/File2", "Starter/policySummary", "Default/File1", "Default/File2", "Default/policySummary", "evaluationSummary"}, suitesNames)
+	assert.Contains(t, *jUnitOutput.TestSuites[3].Properties, property{Name: "isGating", Value: "true"})
+
+	sarifOutput, err := getSarifOutput(&formattedOutput, "1.0.0")
+	assert.Nil(t, err)
+	assert.Contains(t, sarifOutput, `"id": "datree/Starter/"`)
+	assert.Contains(t, sarifOutput, `"id": "datree/Default/"`)
+}
+
 func createAdditionalJUnitData() AdditionalJUnitData {
 	dr, err := defaultRules.GetDefaultRules()
 	if err != nil {
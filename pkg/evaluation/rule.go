@@ -1,6 +1,9 @@
 package evaluation
 
-import "github.com/datreeio/datree/pkg/cliClient"
+import (
+	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/exceptions"
+)
 
 type Rule struct {
 	Identifier         string
@@ -8,6 +11,8 @@ type Rule struct {
 	MessageOnFailure   string
 	DocumentationUrl   string
 	OccurrencesDetails []OccurrenceDetails
+	IsWarning          bool
+	Severity           string
 }
 
 func (rp *Rule) GetFailedOccurrencesCount() int {
@@ -28,4 +33,10 @@ type OccurrenceDetails struct {
 	IsSkipped                 bool                        `yaml:"isSkipped" json:"isSkipped" xml:"isSkipped"`
 	FailureLocations          []cliClient.FailureLocation `yaml:"failureLocations" json:"failureLocations" xml:"failureLocations"`
 	ValidationFailureMessages []string                    `yaml:"validationFailureMessages" json:"validationFailureMessages" xml:"validationFailureMessages"`
+	// Exception is set when the occurrences are skipped by an exception of the exceptions file
+	Exception *exceptions.Exception `yaml:"exception,omitempty" json:"exception,omitempty" xml:"exception,omitempty"`
+	// DocumentIndex is set when the resource was rendered from a source, like a helm chart template
+	DocumentIndex *int `yaml:"documentIndex,omitempty" json:"documentIndex,omitempty" xml:"documentIndex,omitempty"`
+	// RenderedFrom is set when the resource was rendered from a helm chart or kustomization directory by the render mode
+	RenderedFrom string `yaml:"renderedFrom,omitempty" json:"renderedFrom,omitempty" xml:"renderedFrom,omitempty"`
 }
@@ -0,0 +1,224 @@
+package evaluation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/extractor"
+	"k8s.io/utils/strings/slices"
+)
+
+// skip annotations are datree.skip/<RULE_ID>, datree.skip/category.<CATEGORY> or datree.skip/* to skip every rule.
+// The value is the skip message, optionally with options separated by semicolons:
+// "container=sidecar" skips only the failures of that container, "until=2026-12-31" makes the skip expire after that day
+const (
+	skipAllRulesTarget       = "*"
+	skipCategoryTargetPrefix = "category."
+
+	skipContainerOption = "container"
+	skipUntilOption     = "until"
+	skipUntilDateLayout = "2006-01-02"
+)
+
+// SkipAnnotationsRuleIdentifier is the identifier of the warning that reports expired, unused and invalid skip annotations
+const SkipAnnotationsRuleIdentifier = "DATREE_SKIP_ANNOTATIONS"
+
+const skipAnnotationsSchemaPath = "/metadata/annotations"
+
+var skipOptionRegex = regexp.MustCompile(`^(container|until)=(.*)$`)
+
+var containersKeys = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// now is replaced in tests
+var now = time.Now
+
+type skipAnnotation struct {
+	key       string
+	target    string
+	message   string
+	container string
+	// schemaPath scopes inline ignores to the failures on the commented node or under it
+	schemaPath string
+	until      string
+	isExpired  bool
+	// problem is set when the annotation is invalid, invalid annotations don't skip anything
+	problem string
+	isUsed  bool
+}
+
+func extractSkipAnnotations(configuration extractor.Configuration) []*skipAnnotation {
+	var skipAnnotations []*skipAnnotation
+
+	for annotationKey, annotationValue := range configuration.Annotations {
+		if !strings.Contains(annotationKey, SKIP_RULE_PREFIX) {
+			continue
+		}
+		value, _ := annotationValue.(string)
+		skipAnnotations = append(skipAnnotations, newSkipAnnotation(annotationKey, value))
+	}
+	skipAnnotations = append(skipAnnotations, extractInlineIgnores(configuration.YamlNode)...)
+
+	// the most specific annotation is used when several annotations skip the same failure
+	sort.SliceStable(skipAnnotations, func(i, j int) bool {
+		if skipAnnotations[i].specificity() != skipAnnotations[j].specificity() {
+			return skipAnnotations[i].specificity() > skipAnnotations[j].specificity()
+		}
+		return skipAnnotations[i].key < skipAnnotations[j].key
+	})
+
+	return skipAnnotations
+}
+
+func newSkipAnnotation(key string, value string) *skipAnnotation {
+	annotation := &skipAnnotation{
+		key:     key,
+		target:  key[strings.Index(key, SKIP_RULE_PREFIX)+len(SKIP_RULE_PREFIX):],
+		message: value,
+	}
+
+	var messageParts []string
+	hasOptions := false
+	for _, part := range strings.Split(value, ";") {
+		option := skipOptionRegex.FindStringSubmatch(strings.TrimSpace(part))
+		if option == nil {
+			messageParts = append(messageParts, strings.TrimSpace(part))
+			continue
+		}
+
+		hasOptions = true
+		switch option[1] {
+		case skipContainerOption:
+			annotation.container = strings.TrimSpace(option[2])
+		case skipUntilOption:
+			annotation.until = strings.TrimSpace(option[2])
+		}
+	}
+
+	if hasOptions {
+		annotation.message = strings.Join(messageParts, "; ")
+	}
+	if strings.TrimSpace(annotation.message) == "" {
+		annotation.message = "skipped by " + key
+	}
+
+	if annotation.until != "" {
+		untilDate, err := time.Parse(skipUntilDateLayout, annotation.until)
+		if err != nil {
+			annotation.problem = fmt.Sprintf("%s has an invalid until date %q, expected YYYY-MM-DD", key, annotation.until)
+		} else {
+			// the skip is valid through the whole until day
+			annotation.isExpired = !now().UTC().Before(untilDate.AddDate(0, 0, 1))
+		}
+	}
+
+	return annotation
+}
+
+func (annotation *skipAnnotation) specificity() int {
+	if annotation.target == skipAllRulesTarget {
+		return 0
+	}
+	if strings.HasPrefix(annotation.target, skipCategoryTargetPrefix) {
+		return 1
+	}
+	return 2
+}
+
+func (annotation *skipAnnotation) isActive() bool {
+	return annotation.problem == "" && !annotation.isExpired
+}
+
+func (annotation *skipAnnotation) appliesTo(rule policy_factory.RuleWithSchema) bool {
+	if annotation.target == skipAllRulesTarget || annotation.target == rule.RuleIdentifier {
+		return true
+	}
+	if strings.HasPrefix(annotation.target, skipCategoryTargetPrefix) {
+		category := strings.TrimPrefix(annotation.target, skipCategoryTargetPrefix)
+		for _, ruleCategory := range rule.Categories {
+			if strings.EqualFold(category, ruleCategory) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skips returns whether the annotation skips the failure, scoped annotations skip only the failures in their container or under their node
+func (annotation *skipAnnotation) skips(failureLocation cliClient.FailureLocation, configurationJson interface{}) bool {
+	if annotation.schemaPath != "" && failureLocation.SchemaPath != annotation.schemaPath && !strings.HasPrefix(failureLocation.SchemaPath, annotation.schemaPath+"/") {
+		return false
+	}
+	if annotation.container == "" {
+		return true
+	}
+	return getContainerName(configurationJson, failureLocation.SchemaPath) == annotation.container
+}
+
+// getSkipAnnotationsForRule returns the annotations that can skip failures of the rule, the most specific first
+func getSkipAnnotationsForRule(skipAnnotations []*skipAnnotation, rule policy_factory.RuleWithSchema) []*skipAnnotation {
+	var ruleSkipAnnotations []*skipAnnotation
+	for _, annotation := range skipAnnotations {
+		if annotation.isActive() && annotation.appliesTo(rule) {
+			ruleSkipAnnotations = append(ruleSkipAnnotations, annotation)
+		}
+	}
+	return ruleSkipAnnotations
+}
+
+// getSkippingAnnotation returns the first annotation that skips the failure, or nil when the failure isn't skipped
+func getSkippingAnnotation(skipAnnotations []*skipAnnotation, failureLocation cliClient.FailureLocation, configurationJson interface{}) *skipAnnotation {
+	for _, annotation := range skipAnnotations {
+		if annotation.skips(failureLocation, configurationJson) {
+			return annotation
+		}
+	}
+	return nil
+}
+
+// getContainerName returns the name of the container that the schema path points into, or an empty string
+func getContainerName(configurationJson interface{}, schemaPath string) string {
+	containerName := ""
+	current := configurationJson
+	segments := strings.Split(strings.TrimPrefix(schemaPath, "/"), "/")
+	for index, segment := range segments {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			// json pointer escaping
+			current = value[strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")]
+		case []interface{}:
+			itemIndex, err := strconv.Atoi(segment)
+			if err != nil || itemIndex < 0 || itemIndex >= len(value) {
+				return containerName
+			}
+			current = value[itemIndex]
+			if index > 0 && slices.Contains(containersKeys, segments[index-1]) {
+				container, _ := current.(map[string]interface{})
+				containerName, _ = container["name"].(string)
+			}
+		default:
+			return containerName
+		}
+	}
+	return containerName
+}
+
+// getSkipAnnotationsProblems returns a message for each expired, invalid or unused skip annotation of a configuration
+func getSkipAnnotationsProblems(skipAnnotations []*skipAnnotation) []string {
+	var problems []string
+	for _, annotation := range skipAnnotations {
+		if annotation.problem != "" {
+			problems = append(problems, annotation.problem)
+		} else if annotation.isExpired {
+			problems = append(problems, fmt.Sprintf("%s expired on %s, the skipped failures fail again", annotation.key, annotation.until))
+		} else if !annotation.isUsed {
+			problems = append(problems, fmt.Sprintf("%s is unused, it didn't skip any failure", annotation.key))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,146 @@
+package evaluation
+
+import (
+	"testing"
+	"time"
+
+	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/datreeio/datree/pkg/cliClient"
+	"github.com/datreeio/datree/pkg/extractor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSkipAnnotation(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	annotation := newSkipAnnotation("datree.skip/RULE", "known issue")
+	assert.Equal(t, "RULE", annotation.target)
+	assert.Equal(t, "known issue", annotation.message)
+	assert.True(t, annotation.isActive())
+
+	annotation = newSkipAnnotation("datree.skip/RULE", "mesh sidecar; container=sidecar; until=2026-06-01")
+	assert.Equal(t, "mesh sidecar", annotation.message)
+	assert.Equal(t, "sidecar", annotation.container)
+	assert.True(t, annotation.isActive())
+
+	annotation = newSkipAnnotation("datree.skip/*", "until=2026-05-31")
+	assert.Equal(t, "skipped by datree.skip/*", annotation.message)
+	assert.True(t, annotation.isExpired)
+
+	annotation = newSkipAnnotation("datree.skip/RULE", "until=tomorrow")
+	assert.False(t, annotation.isActive())
+	assert.Equal(t, []string{"datree.skip/RULE has an invalid until date \"tomorrow\", expected YYYY-MM-DD"}, getSkipAnnotationsProblems([]*skipAnnotation{annotation}))
+}
+
+func TestSkipAnnotationAppliesTo(t *testing.T) {
+	rule := policy_factory.RuleWithSchema{RuleIdentifier: "CONTAINERS_MISSING_LIMITS", Categories: []string{"Resources"}}
+
+	assert.True(t, newSkipAnnotation("datree.skip/CONTAINERS_MISSING_LIMITS", "").appliesTo(rule))
+	assert.True(t, newSkipAnnotation("datree.skip/category.resources", "").appliesTo(rule))
+	assert.True(t, newSkipAnnotation("datree.skip/*", "").appliesTo(rule))
+	assert.False(t, newSkipAnnotation("datree.skip/category.Security", "").appliesTo(rule))
+	assert.False(t, newSkipAnnotation("datree.skip/OTHER_RULE", "").appliesTo(rule))
+}
+
+func TestGetContainerName(t *testing.T) {
+	configurationJson := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app"},
+				map[string]interface{}{"name": "sidecar"},
+			},
+			"initContainers": []interface{}{
+				map[string]interface{}{"name": "init"},
+			},
+		},
+	}
+
+	assert.Equal(t, "sidecar", getContainerName(configurationJson, "/spec/containers/1/resources/limits"))
+	assert.Equal(t, "init", getContainerName(configurationJson, "/spec/initContainers/0"))
+	assert.Equal(t, "", getContainerName(configurationJson, "/spec"))
+	assert.Equal(t, "", getContainerName(configurationJson, "/spec/containers/5"))
+}
+
+func TestEvaluateWithSkipAnnotations(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	missingLimitsSchema := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"template": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"properties": map[string]interface{}{
+									"containers": map[string]interface{}{
+										"items": map[string]interface{}{"required": []interface{}{"resources"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	rules := []policy_factory.RuleWithSchema{
+		{RuleIdentifier: "CONTAINERS_MISSING_LIMITS", RuleName: "Ensure containers have limits", Schema: missingLimitsSchema, MessageOnFailure: "Add limits", Categories: []string{"Resources"}},
+	}
+
+	configurations, absolutePath, invalidFile := extractor.ExtractConfigurationsFromYamlFile("./test_fixtures/skipAnnotationsConfigurations.yaml")
+	if invalidFile != nil {
+		t.Fatal(invalidFile.ValidationErrors[0])
+	}
+
+	evaluator := New(&mockCliClient{}, nil)
+	policyCheckResultData, err := evaluator.Evaluate(PolicyCheckData{
+		FilesConfigurations: []*extractor.FileConfigurations{{FileName: absolutePath, Configurations: *configurations}},
+		Policy:              policy_factory.Policy{Name: "Default", Rules: rules},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configurationsByName := make(map[string][]cliClient.Configuration)
+	for _, configuration := range policyCheckResultData.RawResults[absolutePath]["CONTAINERS_MISSING_LIMITS"].Configurations {
+		configurationsByName[configuration.Name] = append(configurationsByName[configuration.Name], configuration)
+	}
+
+	// only the sidecar failure is skipped
+	assert.Equal(t, 2, len(configurationsByName["scoped"]))
+	for _, configuration := range configurationsByName["scoped"] {
+		assert.Equal(t, 1, configuration.Occurrences)
+		if configuration.IsSkipped {
+			assert.Equal(t, "the sidecar is managed by the mesh", configuration.SkipMessage)
+			assert.Equal(t, "/spec/template/spec/containers/1", configuration.FailureLocations[0].SchemaPath)
+		} else {
+			assert.Equal(t, "/spec/template/spec/containers/0", configuration.FailureLocations[0].SchemaPath)
+		}
+	}
+
+	assert.Equal(t, 1, len(configurationsByName["category"]))
+	assert.True(t, configurationsByName["category"][0].IsSkipped)
+	assert.Equal(t, "limits are set by the namespace LimitRange", configurationsByName["category"][0].SkipMessage)
+
+	// the expired skip fails again
+	assert.Equal(t, 1, len(configurationsByName["expired"]))
+	assert.False(t, configurationsByName["expired"][0].IsSkipped)
+
+	skipAnnotationsWarning := policyCheckResultData.RawResults[absolutePath][SkipAnnotationsRuleIdentifier]
+	assert.True(t, skipAnnotationsWarning.IsWarning)
+	problemsByName := make(map[string][]string)
+	for _, configuration := range skipAnnotationsWarning.Configurations {
+		problemsByName[configuration.Name] = configuration.ValidationFailureMessages
+	}
+	assert.Equal(t, map[string][]string{
+		"category": {"datree.skip/UNRELATED_RULE is unused, it didn't skip any failure"},
+		"expired":  {"datree.skip/* expired on 2026-01-31, the skipped failures fail again"},
+	}, problemsByName)
+
+	summary := policyCheckResultData.FormattedResults.EvaluationResults.Summary
+	assert.Equal(t, 1, summary.TotalFailedRules)
+	assert.Equal(t, 1, summary.TotalWarningRules)
+	assert.Equal(t, 1, summary.TotalSkippedRules)
+}
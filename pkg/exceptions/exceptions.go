@@ -0,0 +1,197 @@
+// This package loads a central exceptions file, so the exceptions of a project are reviewed in one place instead of in annotations
+
+package exceptions
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"github.com/ghodss/yaml"
+)
+
+const exceptionsApiVersion = "v1"
+
+const expiresDateLayout = "2006-01-02"
+
+// now is replaced in tests
+var now = time.Now
+
+type Exceptions struct {
+	ApiVersion string      `json:"apiVersion"`
+	Exceptions []Exception `json:"exceptions"`
+}
+
+// Exception skips the failures of a rule on the resources it matches.
+// Kind, Namespace, Name and File are optional, Namespace and Name are globs and File is a glob that supports **
+type Exception struct {
+	Rule      string `yaml:"rule" json:"rule" xml:"rule"`
+	Kind      string `yaml:"kind,omitempty" json:"kind,omitempty" xml:"kind,omitempty"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty" xml:"namespace,omitempty"`
+	Name      string `yaml:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"`
+	File      string `yaml:"file,omitempty" json:"file,omitempty" xml:"file,omitempty"`
+	Owner     string `yaml:"owner" json:"owner" xml:"owner"`
+	Ticket    string `yaml:"ticket,omitempty" json:"ticket,omitempty" xml:"ticket,omitempty"`
+	Reason    string `yaml:"reason" json:"reason" xml:"reason"`
+	// Expires is a YYYY-MM-DD date, the exception is valid through that day
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty" xml:"expires,omitempty"`
+}
+
+func Load(path string) (*Exceptions, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exceptions file %s: %s", path, err.Error())
+	}
+
+	var exceptions Exceptions
+	err = yaml.Unmarshal(content, &exceptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exceptions file %s: %s", path, err.Error())
+	}
+	if exceptions.ApiVersion != exceptionsApiVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q in exceptions file %s", exceptions.ApiVersion, path)
+	}
+
+	for index, exception := range exceptions.Exceptions {
+		err = exception.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid exception %d in exceptions file %s: %s", index, path, err.Error())
+		}
+	}
+
+	return &exceptions, nil
+}
+
+func (exception Exception) validate() error {
+	if exception.Rule == "" {
+		return fmt.Errorf("rule is required")
+	}
+	if exception.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if exception.Reason == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if exception.Expires != "" {
+		if _, err := time.Parse(expiresDateLayout, exception.Expires); err != nil {
+			return fmt.Errorf("invalid expires date %q, expected YYYY-MM-DD", exception.Expires)
+		}
+	}
+	for _, glob := range []string{exception.Namespace, exception.Name, exception.File} {
+		if _, err := doublestar.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q", glob)
+		}
+	}
+	return nil
+}
+
+func (exception Exception) IsExpired() bool {
+	if exception.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse(expiresDateLayout, exception.Expires)
+	if err != nil {
+		return false
+	}
+	return !now().UTC().Before(expires.AddDate(0, 0, 1))
+}
+
+// MatchesResource returns whether the exception applies to the resource, filePaths are the paths that the file glob is matched against
+func (exception Exception) MatchesResource(kind string, namespace string, name string, filePaths []string) bool {
+	if exception.Kind != "" && exception.Kind != kind {
+		return false
+	}
+	if !matchesGlob(exception.Namespace, []string{namespace}) || !matchesGlob(exception.Name, []string{name}) || !matchesGlob(exception.File, filePaths) {
+		return false
+	}
+	return true
+}
+
+// MatchesRule returns whether the exception applies to the rule, * matches every rule
+func (exception Exception) MatchesRule(ruleIdentifier string) bool {
+	return exception.Rule == "*" || exception.Rule == ruleIdentifier
+}
+
+func matchesGlob(glob string, values []string) bool {
+	if glob == "" {
+		return true
+	}
+	for _, value := range values {
+		if matched, _ := doublestar.Match(glob, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipMessage is the skip message of the failures that the exception skips
+func (exception Exception) SkipMessage() string {
+	return fmt.Sprintf("%s (%s)", exception.Reason, exception.getMetadataText())
+}
+
+// Description is a single line description of the exception, used in reports and errors
+func (exception Exception) Description() string {
+	var scope []string
+	for _, field := range []struct{ name, value string }{{"kind", exception.Kind}, {"namespace", exception.Namespace}, {"name", exception.Name}, {"file", exception.File}} {
+		if field.value != "" {
+			scope = append(scope, field.name+": "+field.value)
+		}
+	}
+	if len(scope) == 0 {
+		scope = append(scope, "all resources")
+	}
+	return fmt.Sprintf("%s [%s] (%s)", exception.Rule, strings.Join(scope, ", "), exception.getMetadataText())
+}
+
+func (exception Exception) getMetadataText() string {
+	metadata := []string{"owner: " + exception.Owner}
+	if exception.Ticket != "" {
+		metadata = append(metadata, "ticket: "+exception.Ticket)
+	}
+	if exception.Expires != "" {
+		metadata = append(metadata, "expires: "+exception.Expires)
+	}
+	return strings.Join(metadata, ", ")
+}
+
+// Expired returns the expired exceptions, they don't skip failures and fail the run until they are renewed or removed
+func (exceptions *Exceptions) Expired() []*Exception {
+	var expiredExceptions []*Exception
+	for index := range exceptions.Exceptions {
+		if exceptions.Exceptions[index].IsExpired() {
+			expiredExceptions = append(expiredExceptions, &exceptions.Exceptions[index])
+		}
+	}
+	return expiredExceptions
+}
+
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusUnused  = "unused"
+)
+
+type ReportEntry struct {
+	Exception *Exception `json:"exception"`
+	Status    string     `json:"status"`
+	// SkippedCount is the number of failures that the exception skipped in the run
+	SkippedCount int `json:"skippedCount"`
+}
+
+// Report returns the status of each exception according to the number of failures it skipped in the run
+func (exceptions *Exceptions) Report(skippedCountByException map[*Exception]int) []ReportEntry {
+	var report []ReportEntry
+	for index := range exceptions.Exceptions {
+		exception := &exceptions.Exceptions[index]
+		entry := ReportEntry{Exception: exception, Status: StatusActive, SkippedCount: skippedCountByException[exception]}
+		if exception.IsExpired() {
+			entry.Status = StatusExpired
+		} else if entry.SkippedCount == 0 {
+			entry.Status = StatusUnused
+		}
+		report = append(report, entry)
+	}
+	return report
+}
@@ -0,0 +1,57 @@
+package exceptions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	exceptions, err := Load("./test_fixtures/exceptions.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(exceptions.Exceptions))
+	assert.Equal(t, Exception{
+		Rule:    "CONTAINERS_MISSING_MEMORY_LIMIT_KEY",
+		Kind:    "Deployment",
+		Name:    "web",
+		Owner:   "platform-team",
+		Ticket:  "SEC-1234",
+		Reason:  "limits are set by the LimitRange",
+		Expires: "2027-03-31",
+	}, exceptions.Exceptions[0])
+
+	_, err = Load("./test_fixtures/missingOwner.yaml")
+	assert.EqualError(t, err, "invalid exception 0 in exceptions file ./test_fixtures/missingOwner.yaml: owner is required")
+}
+
+func TestMatches(t *testing.T) {
+	exception := Exception{Rule: "RULE", Kind: "Deployment", Namespace: "prod-*", File: "manifests/**"}
+
+	assert.True(t, exception.MatchesResource("Deployment", "prod-eu", "web", []string{"/repo/manifests/web/deployment.yaml", "manifests/web/deployment.yaml"}))
+	assert.False(t, exception.MatchesResource("StatefulSet", "prod-eu", "web", []string{"manifests/web.yaml"}))
+	assert.False(t, exception.MatchesResource("Deployment", "staging", "web", []string{"manifests/web.yaml"}))
+	assert.False(t, exception.MatchesResource("Deployment", "prod-eu", "web", []string{"legacy/web.yaml"}))
+
+	assert.True(t, exception.MatchesRule("RULE"))
+	assert.False(t, exception.MatchesRule("OTHER_RULE"))
+	assert.True(t, Exception{Rule: "*"}.MatchesRule("OTHER_RULE"))
+}
+
+func TestReport(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	exceptions, err := Load("./test_fixtures/exceptions.yaml")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []*Exception{&exceptions.Exceptions[2]}, exceptions.Expired())
+
+	report := exceptions.Report(map[*Exception]int{&exceptions.Exceptions[0]: 2, &exceptions.Exceptions[2]: 1})
+	var statuses []string
+	for _, entry := range report {
+		statuses = append(statuses, entry.Status)
+	}
+	assert.Equal(t, []string{StatusActive, StatusUnused, StatusExpired}, statuses)
+	assert.Equal(t, 2, report[0].SkippedCount)
+}
@@ -74,6 +74,19 @@ func (c *CommandRunner) ExecuteKustomizeBin(args []string) ([]byte, error) {
 	}
 }
 
+// ExecuteHelmBin renders a chart with helm template, args are the chart and the flags of helm template
+func (c *CommandRunner) ExecuteHelmBin(args []string) ([]byte, error) {
+	if !c.commandExists("helm") {
+		return nil, errors.New("helm is not installed")
+	}
+	commandOutput, err := c.RunCommand("helm", append([]string{"template"}, args...))
+	if err != nil {
+		return nil, fmt.Errorf("helm template errored: %s",
+			commandOutput.ErrorOutput.String())
+	}
+	return commandOutput.ResultOutput.Bytes(), nil
+}
+
 func (c *CommandRunner) commandExists(cmd string) bool {
 	_, err := exec.LookPath(cmd)
 	return err == nil
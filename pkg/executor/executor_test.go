@@ -102,3 +102,17 @@ func TestCommandRunner_ExecuteKustomizeBin(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandRunner_ExecuteHelmBin(t *testing.T) {
+	t.Run("should return error if helm is not installed", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		c := CreateNewCommandRunner()
+		got, err := c.ExecuteHelmBin([]string{"./chart"})
+		if !reflect.DeepEqual(err, errors.New("helm is not installed")) {
+			t.Errorf("CommandRunner.ExecuteHelmBin() error = %v, want %v", err, errors.New("helm is not installed"))
+		}
+		if got != nil {
+			t.Errorf("CommandRunner.ExecuteHelmBin() = %v, want nil", got)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 	k8sSigsYaml "sigs.k8s.io/yaml"
@@ -69,8 +70,19 @@ type Configuration struct {
 	Annotations  map[string]interface{}
 	Payload      []byte
 	YamlNode     yaml.Node
+	// Source is the file that the configuration was rendered from, like the chart template of a helm `# Source:` comment
+	Source string
+	// DocumentIndex is the index of the configuration among the configurations that were rendered from its source
+	DocumentIndex int
+	// RenderedFrom is the helm chart or kustomization directory that the configuration was rendered from, like a `# Rendered from:` comment sets
+	RenderedFrom string
 }
 
+const (
+	SourceComment       = "# Source: "
+	RenderedFromComment = "# Rendered from: "
+)
+
 type FileConfigurations struct {
 	FileName       string          `json:"fileName"`
 	Configurations []Configuration `json:"configurations"`
@@ -87,6 +99,8 @@ func ParseYaml(content string) (*[]Configuration, error) {
 
 func extractYamlConfigurations(content string) (*[]Configuration, error) {
 	var configurations []Configuration
+	contentLines := strings.Split(content, "\n")
+	documentsCountBySource := make(map[string]int)
 
 	yamlDecoder := yaml.NewDecoder(bytes.NewReader([]byte(content)))
 
@@ -118,12 +132,36 @@ func extractYamlConfigurations(content string) (*[]Configuration, error) {
 			continue
 		}
 
-		configurations = append(configurations, extractConfigurationK8sData(jsonByte, yamlNode))
+		configuration := extractConfigurationK8sData(jsonByte, yamlNode)
+		if len(yamlNode.Content) > 0 {
+			configuration.Source = getDocumentComment(contentLines, yamlNode.Content[0].Line, SourceComment)
+			configuration.RenderedFrom = getDocumentComment(contentLines, yamlNode.Content[0].Line, RenderedFromComment)
+		}
+		if configuration.Source != "" {
+			configuration.DocumentIndex = documentsCountBySource[configuration.Source]
+			documentsCountBySource[configuration.Source]++
+		}
+		configurations = append(configurations, configuration)
 	}
 
 	return &configurations, nil
 }
 
+// getDocumentComment returns the value of a `<prefix><value>` comment in the comments above the document content,
+// like the `# Source: <path>` comments that helm writes above every rendered document
+func getDocumentComment(contentLines []string, contentLine int, prefix string) string {
+	for index := contentLine - 2; index >= 0 && index < len(contentLines); index-- {
+		line := strings.TrimSpace(contentLines[index])
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break
+		}
+	}
+	return ""
+}
+
 func extractConfigurationK8sData(content []byte, yamlNode yaml.Node) Configuration {
 	var configuration Configuration
 	var jsonObject map[string]interface{}
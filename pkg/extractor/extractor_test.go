@@ -130,4 +130,32 @@ func TestExtractConfigurationsFromYamlFile(t *testing.T) {
 		assert.Equal(t, "Deployment", firstConfiguration.Kind)
 		assert.Equal(t, "apps/v1", firstConfiguration.ApiVersion)
 	})
+	t.Run("should extract the sources of helm rendered documents", func(t *testing.T) {
+		path := "./extractorTestFiles/helmRendered.yaml"
+		configurations, _, err := ExtractConfigurationsFromYamlFile(path)
+
+		assert.Nil(t, err)
+		var sources []string
+		var documentIndexes []int
+		for _, configuration := range *configurations {
+			sources = append(sources, configuration.Source)
+			documentIndexes = append(documentIndexes, configuration.DocumentIndex)
+		}
+		assert.Equal(t, []string{"rss/templates/serviceaccount.yaml", "rss/templates/deployment.yaml", "rss/templates/deployment.yaml", ""}, sources)
+		assert.Equal(t, []int{0, 0, 1, 0}, documentIndexes)
+	})
+	t.Run("should extract the chart or kustomization that documents were rendered from", func(t *testing.T) {
+		path := "./extractorTestFiles/renderedFrom.yaml"
+		configurations, _, err := ExtractConfigurationsFromYamlFile(path)
+
+		assert.Nil(t, err)
+		var sources []string
+		var renderedFrom []string
+		for _, configuration := range *configurations {
+			sources = append(sources, configuration.Source)
+			renderedFrom = append(renderedFrom, configuration.RenderedFrom)
+		}
+		assert.Equal(t, []string{"deploy/charts/rss/templates/serviceaccount.yaml", "deploy/charts/rss/templates/deployment.yaml"}, sources)
+		assert.Equal(t, []string{"deploy/charts/rss", "deploy/charts/rss"}, renderedFrom)
+	})
 }
@@ -5,26 +5,59 @@ package jsonSchemaValidator
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/google/cel-go/cel"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"k8s.io/apiserver/pkg/cel/environment"
 )
 
 const CELDefinitionCustomKey = "CELDefinition"
 
-type CustomKeyCELDefinitionCompiler struct{}
+// CELParamsCustomKey holds the value exposed to the CEL expressions as the params variable, like a ValidatingAdmissionPolicy param
+const CELParamsCustomKey = "CELParams"
 
-type CustomKeyCELDefinitionSchema []interface{}
+// CustomKeyCELDefinitionCompiler compiles cel rules, cross resource rules see the resources of the Inventory
+type CustomKeyCELDefinitionCompiler struct {
+	Inventory *Inventory
+}
+
+// CustomKeyCELDefinitionSchema holds the CEL programs compiled and type-checked at compile time,
+// cel programs are safe for concurrent use
+type CustomKeyCELDefinitionSchema struct {
+	expressions []compiledCELExpression
+	params      interface{}
+	// resources is set only for cross resource rules
+	resources map[string]interface{}
+}
+
+type compiledCELExpression struct {
+	expression        string
+	program           cel.Program
+	message           string
+	messageExpression cel.Program
+	reason            string
+}
+
+// the reasons a ValidatingAdmissionPolicy validation can return
+var validCELReasons = map[string]bool{"Unauthorized": true, "Forbidden": true, "Invalid": true, "RequestEntityTooLarge": true}
 
 var CustomKeyCELRule = jsonschema.MustCompileString("customKeyCELDefinition.json", `{
 	"properties" : {
 		"CELDefinition": {
 			"type": "array"
+		},
+		"CELParams": {
+			"type": ["object", "null"]
+		},
+		"crossResource": {
+			"type": "boolean"
 		}
 	}
 }`)
 
-func (CustomKeyCELDefinitionCompiler) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
+func (compiler CustomKeyCELDefinitionCompiler) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
 	if customKeyCELRule, ok := m[CELDefinitionCustomKey]; ok {
 		customKeyCELRuleObj, validObject := customKeyCELRule.([]interface{})
 		if !validObject {
@@ -40,72 +73,170 @@ func (CustomKeyCELDefinitionCompiler) Compile(ctx jsonschema.CompilerContext, m
 			return nil, fmt.Errorf("CELDefinition can't be empty")
 		}
 
-		return CustomKeyCELDefinitionSchema(customKeyCELRuleObj), nil
+		crossResource, err := isCrossResource(m)
+		if err != nil {
+			return nil, err
+		}
+
+		var resources map[string]interface{}
+		getEnv := getCELEnv
+		if crossResource {
+			resources = NewInventory(nil).Resources()
+			if compiler.Inventory != nil {
+				resources = compiler.Inventory.Resources()
+			}
+			getEnv = getCrossResourceCELEnv
+		}
+
+		env, err := getEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		compiledExpressions := make([]compiledCELExpression, 0, len(CELDefinitionSchema.CELExpressions))
+		for _, celExpression := range CELDefinitionSchema.CELExpressions {
+			compiledExpression, err := compileCELExpression(env, celExpression)
+			if err != nil {
+				return nil, err
+			}
+			compiledExpressions = append(compiledExpressions, compiledExpression)
+		}
+
+		params, err := convertCELParams(m[CELParamsCustomKey])
+		if err != nil {
+			return nil, err
+		}
+
+		return CustomKeyCELDefinitionSchema{expressions: compiledExpressions, params: params, resources: resources}, nil
 	}
 	return nil, nil
 }
 
-func (customKeyCELDefinitionSchema CustomKeyCELDefinitionSchema) Validate(ctx jsonschema.ValidationContext, dataValue interface{}) error {
-	CELDefinitionSchema, err := convertCustomKeyCELDefinitionSchemaToCELDefinitionSchema(customKeyCELDefinitionSchema)
+func compileCELExpression(env *cel.Env, celExpression CELExpression) (compiledCELExpression, error) {
+	program, err := compileCELProgram(env, celExpression.Expression, cel.BoolType)
 	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, err.Error())
+		if err == errUnexpectedCELOutputType {
+			return compiledCELExpression{}, fmt.Errorf("cel expression needs to return a boolean")
+		}
+		return compiledCELExpression{}, fmt.Errorf("cel expression compile error: %s", err)
 	}
-	// wrap dataValue (the resource that should be validated) inside a struct with parent object key
-	resourceWithParentKey := make(map[string]interface{})
-	resourceWithParentKey["object"] = dataValue
 
-	// prepare CEL env inputs - in our case the only input is the resource that should be validated
-	inputs, err := getCELEnvInputs(resourceWithParentKey)
-	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, err.Error())
+	compiledExpression := compiledCELExpression{
+		expression: celExpression.Expression,
+		program:    program,
+		message:    celExpression.Message,
+		reason:     celExpression.Reason,
 	}
 
-	env, err := cel.NewEnv(inputs...)
-	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, err.Error())
+	if celExpression.MessageExpression != "" {
+		compiledExpression.messageExpression, err = compileCELProgram(env, celExpression.MessageExpression, cel.StringType)
+		if err != nil {
+			if err == errUnexpectedCELOutputType {
+				return compiledCELExpression{}, fmt.Errorf("cel messageExpression needs to return a string")
+			}
+			return compiledCELExpression{}, fmt.Errorf("cel messageExpression compile error: %s", err)
+		}
 	}
 
-	for _, celExpression := range CELDefinitionSchema.CELExpressions {
-		ast, issues := env.Compile(celExpression.Expression)
-		if issues != nil && issues.Err() != nil {
-			return ctx.Error(CustomKeyValidationErrorKeyPath, "cel expression compile error: %s", issues.Err())
-		}
+	if celExpression.Reason != "" && !validCELReasons[celExpression.Reason] {
+		return compiledCELExpression{}, fmt.Errorf("cel reason must be one of Unauthorized, Forbidden, Invalid, RequestEntityTooLarge")
+	}
 
-		prg, err := env.Program(ast)
-		if err != nil {
-			return ctx.Error(CustomKeyValidationErrorKeyPath, "cel program construction error: %s", err)
-		}
+	return compiledExpression, nil
+}
 
-		res1, _, err := prg.Eval(resourceWithParentKey)
-		if err != nil {
-			return ctx.Error(CustomKeyValidationErrorKeyPath, "cel evaluation error: %s", err)
-		}
+var errUnexpectedCELOutputType = fmt.Errorf("unexpected cel output type")
+
+func compileCELProgram(env *cel.Env, expression string, outputType *cel.Type) (cel.Program, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	// a dyn output, like a field of the object, is checked when the program runs
+	if !outputType.IsAssignableType(ast.OutputType()) && !ast.OutputType().IsAssignableType(cel.DynType) {
+		return nil, errUnexpectedCELOutputType
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("cel program construction error: %s", err)
+	}
+	return prg, nil
+}
+
+func (customKeyCELDefinitionSchema CustomKeyCELDefinitionSchema) Validate(ctx jsonschema.ValidationContext, dataValue interface{}) error {
+	// wrap dataValue (the resource that should be validated) inside a struct with parent object key
+	resourceWithParentKey := make(map[string]interface{})
+	resourceWithParentKey["object"] = dataValue
+	resourceWithParentKey["params"] = customKeyCELDefinitionSchema.params
+	if customKeyCELDefinitionSchema.resources != nil {
+		resourceWithParentKey["resources"] = customKeyCELDefinitionSchema.resources
+	}
 
-		if res1.Type().TypeName() != "bool" {
-			return ctx.Error(CustomKeyValidationErrorKeyPath, "cel expression needs to return a boolean")
+	// like ValidatingAdmissionPolicy, all the expressions are evaluated and each failure is reported separately
+	var failedExpressionsErrors []error
+	for _, celExpression := range customKeyCELDefinitionSchema.expressions {
+		res1, _, err := celExpression.program.Eval(resourceWithParentKey)
+		if err != nil {
+			failedExpressionsErrors = append(failedExpressionsErrors, ctx.Error(CustomKeyValidationErrorKeyPath, "cel evaluation error: %s", err))
+			continue
 		}
 
 		celReturnValue, ok := res1.Value().(bool)
 		if !ok {
-			return ctx.Error(CustomKeyValidationErrorKeyPath, "cel expression needs to return a boolean")
+			failedExpressionsErrors = append(failedExpressionsErrors, ctx.Error(CustomKeyValidationErrorKeyPath, "cel expression needs to return a boolean"))
+			continue
 		}
 		if !celReturnValue {
-			return ctx.Error(CELDefinitionCustomKey, "values in data value %v do not match", dataValue)
+			failedExpressionsErrors = append(failedExpressionsErrors, ctx.Error(CustomKeyValidationErrorKeyPath, "%s", celExpression.getFailureMessage(resourceWithParentKey)))
 		}
 	}
 
-	return nil
+	if len(failedExpressionsErrors) == 0 {
+		return nil
+	}
+	if len(failedExpressionsErrors) == 1 {
+		return failedExpressionsErrors[0]
+	}
+	return jsonschema.ValidationError{}.Group(ctx.Error(CELDefinitionCustomKey, "%d cel expressions failed", len(failedExpressionsErrors)), failedExpressionsErrors...)
+}
+
+// getFailureMessage follows ValidatingAdmissionPolicy: a messageExpression that evaluates to a non-empty string wins,
+// otherwise the static message is used, and without any message the failed expression itself is reported
+func (celExpression compiledCELExpression) getFailureMessage(activation map[string]interface{}) string {
+	message := celExpression.message
+	if celExpression.messageExpression != nil {
+		res, _, err := celExpression.messageExpression.Eval(activation)
+		if err == nil {
+			if evaluatedMessage, ok := res.Value().(string); ok && strings.TrimSpace(evaluatedMessage) != "" && !strings.Contains(evaluatedMessage, "\n") {
+				message = evaluatedMessage
+			}
+		}
+	}
+
+	if message == "" {
+		message = "failed expression: " + celExpression.expression
+	}
+
+	if celExpression.reason != "" {
+		message = fmt.Sprintf("%s (reason: %s)", message, celExpression.reason)
+	}
+	return message
 }
 
 type CELExpression struct {
-	Expression string `json:"expression"`
+	Expression        string `json:"expression"`
+	Message           string `json:"message"`
+	MessageExpression string `json:"messageExpression"`
+	Reason            string `json:"reason"`
 }
 
 type CELDefinition struct {
 	CELExpressions []CELExpression
 }
 
-func convertCustomKeyCELDefinitionSchemaToCELDefinitionSchema(CELDefinitionSchema CustomKeyCELDefinitionSchema) (*CELDefinition, error) {
+func convertCustomKeyCELDefinitionSchemaToCELDefinitionSchema(CELDefinitionSchema []interface{}) (*CELDefinition, error) {
 	var CELDefinition CELDefinition
 	for _, CELExpressionFromSchema := range CELDefinitionSchema {
 		var CELExpression CELExpression
@@ -123,10 +254,54 @@ func convertCustomKeyCELDefinitionSchemaToCELDefinitionSchema(CELDefinitionSchem
 	return &CELDefinition, nil
 }
 
-func getCELEnvInputs(dataValue map[string]interface{}) ([]cel.EnvOption, error) {
-	inputVars := make([]cel.EnvOption, 0, len(dataValue))
-	for input := range dataValue {
-		inputVars = append(inputVars, cel.Variable(input, cel.DynType))
+// convertCELParams decodes the params numbers, which the schema compiler keeps as json.Number, to types CEL can use
+func convertCELParams(params interface{}) (interface{}, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("CELParams failed to marshal to json, %s", err.Error())
+	}
+
+	var convertedParams interface{}
+	err = json.Unmarshal(b, &convertedParams)
+	if err != nil {
+		return nil, fmt.Errorf("CELParams must be an object %s", err.Error())
 	}
-	return inputVars, nil
+	return convertedParams, nil
+}
+
+var (
+	celEnv     *cel.Env
+	celEnvErr  error
+	celEnvOnce sync.Once
+
+	crossResourceCELEnv     *cel.Env
+	crossResourceCELEnvErr  error
+	crossResourceCELEnvOnce sync.Once
+)
+
+// getCELEnv returns the environment used by ValidatingAdmissionPolicy (strings, lists, regex, url and quantity libraries),
+// extended with the resource that should be validated as the object variable and the rule params as the params variable
+func getCELEnv() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		baseEnv := environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion()).StoredExpressionsEnv()
+		celEnv, celEnvErr = baseEnv.Extend(cel.Variable("object", cel.DynType), cel.Variable("params", cel.DynType))
+	})
+	return celEnv, celEnvErr
+}
+
+// getCrossResourceCELEnv extends the cel environment with the resources of the run as the resources variable
+func getCrossResourceCELEnv() (*cel.Env, error) {
+	crossResourceCELEnvOnce.Do(func() {
+		env, err := getCELEnv()
+		if err != nil {
+			crossResourceCELEnvErr = err
+			return
+		}
+		crossResourceCELEnv, crossResourceCELEnvErr = env.Extend(cel.Variable("resources", cel.MapType(cel.StringType, cel.DynType)))
+	})
+	return crossResourceCELEnv, crossResourceCELEnvErr
 }
@@ -14,19 +14,29 @@ import (
 
 const RegoDefinitionCustomKey = "regoDefinition"
 
-type CustomKeyRegoDefinitionCompiler struct{}
+// CustomKeyRegoDefinitionCompiler compiles rego rules, cross resource rules see the resources of the Inventory
+type CustomKeyRegoDefinitionCompiler struct {
+	Inventory *Inventory
+}
 
-type CustomKeyRegoDefinitionSchema map[string]interface{}
+// CustomKeyRegoDefinitionSchema holds the rego query prepared at compile time.
+// A prepared query is safe for concurrent use, so it's shared by all the validations of the schema
+type CustomKeyRegoDefinitionSchema struct {
+	preparedQuery rego.PreparedEvalQuery
+}
 
 var CustomKeyRegoRule = jsonschema.MustCompileString("customKeyRegoDefinition.json", `{
 	"properties" : {
 		"regoDefinition": {
 			"type": "object"
+		},
+		"crossResource": {
+			"type": "boolean"
 		}
 	}
 }`)
 
-func (CustomKeyRegoDefinitionCompiler) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
+func (compiler CustomKeyRegoDefinitionCompiler) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
 	if customKeyRegoRule, ok := m[RegoDefinitionCustomKey]; ok {
 		customKeyRegoRuleObj, validObject := customKeyRegoRule.(map[string]interface{})
 		if !validObject {
@@ -42,7 +52,31 @@ func (CustomKeyRegoDefinitionCompiler) Compile(ctx jsonschema.CompilerContext, m
 			return nil, fmt.Errorf("regoDefinition.code can't be empty")
 		}
 
-		return CustomKeyRegoDefinitionSchema(customKeyRegoRuleObj), nil
+		crossResource, err := isCrossResource(m)
+		if err != nil {
+			return nil, err
+		}
+
+		var inventory *Inventory
+		if crossResource {
+			inventory = compiler.Inventory
+			if inventory == nil {
+				inventory = NewInventory(nil)
+			}
+		}
+
+		regoObject, err := retrieveRegoFromSchema(regoDefinitionSchema, inventory)
+		if err != nil {
+			return nil, fmt.Errorf("can't compile rego code, %s", err.Error())
+		}
+
+		// parse and compile the rego modules once, instead of on every validation
+		preparedQuery, err := regoObject.PrepareForEval(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("can't compile rego code, %s", err.Error())
+		}
+
+		return CustomKeyRegoDefinitionSchema{preparedQuery: preparedQuery}, nil
 	}
 	return nil, nil
 }
@@ -53,47 +87,104 @@ type RegoDefinition struct {
 }
 
 func (customKeyRegoDefinitionSchema CustomKeyRegoDefinitionSchema) Validate(ctx jsonschema.ValidationContext, dataValue interface{}) error {
-	regoDefinitionSchema, err := convertCustomKeyRegoDefinitionSchemaToRegoDefinitionSchema(customKeyRegoDefinitionSchema)
+	// Execute the prepared query.
+	rs, err := customKeyRegoDefinitionSchema.preparedQuery.Eval(context.Background(), rego.EvalInput(dataValue))
+
 	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, err.Error())
+		return ctx.Error(CustomKeyValidationErrorKeyPath, "failed to evaluate rego due to %s", err.Error())
 	}
 
-	regoCtx := context.Background()
+	if len(rs) != 1 || len(rs[0].Expressions) != 1 {
+		return ctx.Error(CustomKeyValidationErrorKeyPath, "failed to evaluate rego, unexpected results")
+	}
 
-	regoObject, err := retrieveRegoFromSchema(regoDefinitionSchema)
-	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, "can't compile rego code, %s", err.Error())
+	resultValues, ok := rs[0].Expressions[0].Value.([]interface{})
+	if !ok {
+		return ctx.Error(CustomKeyValidationErrorKeyPath, "violation needs to be a partial set rule, e.g. violation[msg] { ... }, returning booleans, strings or objects with a msg")
 	}
 
-	// Create a prepared query that can be evaluated.
-	query, err := regoObject.PrepareForEval(regoCtx)
-	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, "can't compile rego code, %s", err.Error())
+	// violations may be booleans, messages, or objects with a message and an optional path to the failing value
+	var violationsErrors []error
+	isViolatedWithoutMessage := false
+	for _, resultValue := range resultValues {
+		switch violationReturnValue := resultValue.(type) {
+		case bool:
+			if violationReturnValue {
+				isViolatedWithoutMessage = true
+			}
+		case string:
+			violationsErrors = append(violationsErrors, ctx.Error(CustomKeyValidationErrorKeyPath, "%s", violationReturnValue))
+		case map[string]interface{}:
+			violationError, err := getViolationObjectError(ctx, violationReturnValue)
+			if err != nil {
+				return ctx.Error(CustomKeyValidationErrorKeyPath, err.Error())
+			}
+			violationsErrors = append(violationsErrors, violationError)
+		default:
+			return ctx.Error(CustomKeyValidationErrorKeyPath, "violation needs to return a boolean, a string or an object with a msg")
+		}
 	}
 
-	// Execute the prepared query.
-	rs, err := query.Eval(regoCtx, rego.EvalInput(dataValue))
+	if isViolatedWithoutMessage {
+		violationsErrors = append(violationsErrors, ctx.Error(RegoDefinitionCustomKey, "values in data value %v do not match", dataValue))
+	}
 
-	if err != nil {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, "failed to evaluate rego due to %s", err.Error())
+	if len(violationsErrors) == 0 {
+		return nil
+	}
+	if len(violationsErrors) == 1 {
+		return violationsErrors[0]
 	}
+	return jsonschema.ValidationError{}.Group(ctx.Error(RegoDefinitionCustomKey, "found %d violations", len(violationsErrors)), violationsErrors...)
+}
 
-	if len(rs) != 1 || len(rs[0].Expressions) != 1 {
-		return ctx.Error(CustomKeyValidationErrorKeyPath, "failed to evaluate rego, unexpected results")
+func getViolationObjectError(ctx jsonschema.ValidationContext, violation map[string]interface{}) (*jsonschema.ValidationError, error) {
+	msg, ok := violation["msg"].(string)
+	if !ok {
+		return nil, fmt.Errorf("violation object must contain a msg string")
 	}
 
-	resultValues := (rs[0].Expressions[0].Value).([]interface{})
-	for _, resultValue := range resultValues {
-		violationReturnValue, ok := resultValue.(bool)
-		if !ok {
-			return ctx.Error(CustomKeyValidationErrorKeyPath, "violation needs to return a boolean")
+	violationError := ctx.Error(CustomKeyValidationErrorKeyPath, "%s", msg)
+
+	if path, ok := violation["path"]; ok {
+		pathPointer, err := convertViolationPathToJsonPointer(path)
+		if err != nil {
+			return nil, err
+		}
+		// the path is relative to the value that was passed to rego as input
+		violationError.InstanceLocation += pathPointer
+	}
+
+	return violationError, nil
+}
+
+// convertViolationPathToJsonPointer accepts a json pointer ("/spec/containers/0"), a dotted path ("spec.containers[0]")
+// or an array of path segments (["spec", "containers", 0])
+func convertViolationPathToJsonPointer(path interface{}) (string, error) {
+	var segments []string
+	switch pathValue := path.(type) {
+	case string:
+		if strings.HasPrefix(pathValue, "/") {
+			return pathValue, nil
 		}
-		if violationReturnValue {
-			return ctx.Error(RegoDefinitionCustomKey, "values in data value %v do not match", dataValue)
+		dottedPath := strings.NewReplacer("[", ".", "]", "").Replace(pathValue)
+		for _, segment := range strings.Split(dottedPath, ".") {
+			if segment != "" {
+				segments = append(segments, segment)
+			}
 		}
+	case []interface{}:
+		for _, segment := range pathValue {
+			segments = append(segments, fmt.Sprint(segment))
+		}
+	default:
+		return "", fmt.Errorf("violation path must be a string or an array")
 	}
 
-	return nil
+	if len(segments) == 0 {
+		return "", nil
+	}
+	return "/" + strings.Join(segments, "/"), nil
 }
 
 func getPackageFromRegoCode(regoCode string) (string, error) {
@@ -108,7 +199,8 @@ func getPackageFromRegoCode(regoCode string) (string, error) {
 	return packageStr[1], nil
 }
 
-func retrieveRegoFromSchema(regoDefinitionSchema *RegoDefinition) (*rego.Rego, error) {
+// retrieveRegoFromSchema returns the rego rule, with the inventory as data.inventory when an inventory is given
+func retrieveRegoFromSchema(regoDefinitionSchema *RegoDefinition, inventory *Inventory) (*rego.Rego, error) {
 	const mainModuleFileName = "main.rego"
 	const regoFunctionEntryPoint = "violation"
 
@@ -122,18 +214,29 @@ func retrieveRegoFromSchema(regoDefinitionSchema *RegoDefinition) (*rego.Rego, e
 
 	regoObjectParts = append(regoObjectParts, rego.Module(mainModuleFileName, regoDefinitionSchema.Code))
 
-	for _, lib := range regoDefinitionSchema.Libs {
+	moduleNames := map[string]bool{mainModuleFileName: true}
+	for i, lib := range regoDefinitionSchema.Libs {
 		libPackageName, err := getPackageFromRegoCode(lib)
 		if err != nil {
 			return nil, err
 		}
-		regoObjectParts = append(regoObjectParts, rego.Module(libPackageName, lib))
+		// libs may share a package (conftest policies are usually all in package main), and a module name must be unique
+		moduleName := libPackageName
+		if moduleNames[moduleName] {
+			moduleName = fmt.Sprintf("%s_%d", libPackageName, i)
+		}
+		moduleNames[moduleName] = true
+		regoObjectParts = append(regoObjectParts, rego.Module(moduleName, lib))
 	}
+	if inventory != nil {
+		regoObjectParts = append(regoObjectParts, rego.Store(inventory.getRegoStore()))
+	}
+
 	regoObject := rego.New(regoObjectParts...)
 	return regoObject, nil
 }
 
-func convertCustomKeyRegoDefinitionSchemaToRegoDefinitionSchema(regoDefinitionSchema CustomKeyRegoDefinitionSchema) (*RegoDefinition, error) {
+func convertCustomKeyRegoDefinitionSchemaToRegoDefinitionSchema(regoDefinitionSchema map[string]interface{}) (*RegoDefinition, error) {
 	b, err := json.Marshal(regoDefinitionSchema)
 	if err != nil {
 		return nil, fmt.Errorf("regoDefinition failed to marshal to json, %s", err.Error())
@@ -0,0 +1,81 @@
+// This file defines the inventory of resources that cross resource rules can see next to the resource under test
+
+package jsonSchemaValidator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/storage/inmem"
+)
+
+// CrossResourceCustomKey marks a rego or cel rule as a cross resource rule, which can see every resource of the run:
+// rego rules as data.inventory and cel rules as the resources variable
+const CrossResourceCustomKey = "crossResource"
+
+const inventoryRegoDataKey = "inventory"
+
+// Inventory indexes the resources of a run by kind, namespace and name (inventory[kind][namespace][name]),
+// resources without a namespace are indexed under an empty namespace
+type Inventory struct {
+	resources map[string]interface{}
+
+	regoStore     storage.Store
+	regoStoreOnce sync.Once
+}
+
+func NewInventory(resources []interface{}) *Inventory {
+	index := make(map[string]interface{})
+	for _, resource := range resources {
+		resourceObject, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := resourceObject["kind"].(string)
+		metadata, _ := resourceObject["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		namespace, _ := metadata["namespace"].(string)
+		if kind == "" {
+			continue
+		}
+
+		namespaces, ok := index[kind].(map[string]interface{})
+		if !ok {
+			namespaces = make(map[string]interface{})
+			index[kind] = namespaces
+		}
+		names, ok := namespaces[namespace].(map[string]interface{})
+		if !ok {
+			names = make(map[string]interface{})
+			namespaces[namespace] = names
+		}
+		names[name] = resource
+	}
+	return &Inventory{resources: index}
+}
+
+// Resources returns the resources indexed by kind, namespace and name
+func (inventory *Inventory) Resources() map[string]interface{} {
+	return inventory.resources
+}
+
+// getRegoStore returns a store with the inventory, shared by all the rego rules of the run
+func (inventory *Inventory) getRegoStore() storage.Store {
+	inventory.regoStoreOnce.Do(func() {
+		inventory.regoStore = inmem.NewFromObject(map[string]interface{}{inventoryRegoDataKey: inventory.resources})
+	})
+	return inventory.regoStore
+}
+
+func isCrossResource(m map[string]interface{}) (bool, error) {
+	crossResource, ok := m[CrossResourceCustomKey]
+	if !ok || crossResource == nil {
+		return false, nil
+	}
+	isCrossResource, ok := crossResource.(bool)
+	if !ok {
+		return false, fmt.Errorf("crossResource must be a boolean")
+	}
+	return isCrossResource, nil
+}
@@ -58,27 +58,10 @@ func (jsv *JSONSchemaValidator) Validate(schemaContent string, yamlContent []byt
 		return nil, err
 	}
 
-	compiler := jsonschema.NewCompiler()
-	//format is treated as annotation in draft-2019 onwards. it needs to be explicitly enabled by compiler.AssertFormat = true.
-	//see reference: https://github.com/santhosh-tekuri/jsonschema/issues/43
-	compiler.AssertFormat = true
-
-	if err := compiler.AddResource("schema.json", strings.NewReader(schemaContent)); err != nil {
-		return nil, err
-	}
-
-	compiler.RegisterExtension("resourceMinimum", resourceMinimum, resourceMinimumCompiler{})
-	compiler.RegisterExtension("resourceMaximum", resourceMaximum, resourceMaximumCompiler{})
-	compiler.RegisterExtension("customKeyRule81", extensions.CustomKeyRule81, extensions.CustomKeyRule81Compiler{})
-	compiler.RegisterExtension("customKeyRule89", extensions.CustomKeyRule89, extensions.CustomKeyRule89Compiler{})
-	compiler.RegisterExtension("customKeyRule101", extensions.CustomKeyRule101, extensions.CustomKeyRule101Compiler{})
-	compiler.RegisterExtension("customKeyRegoRule", extensions.CustomKeyRegoRule, extensions.CustomKeyRegoDefinitionCompiler{})
-	compiler.RegisterExtension("customKeyCELRule", extensions.CustomKeyCELRule, extensions.CustomKeyCELDefinitionCompiler{})
-
-	// compiler.Compile() is an expensive operation. We cache the compiled schema in rulesSchemasCache to avoid re-compiling the same schema.
+	// Compile() is an expensive operation. We cache the compiled schema in rulesSchemasCache to avoid re-compiling the same schema.
 	schemaAny, ok := jsv.rulesSchemasCache.Load(schemaContent)
 	if !ok {
-		compiledSchema, err := compiler.Compile("schema.json")
+		compiledSchema, err := Compile(schemaContent)
 		if err != nil {
 			return nil, err
 		}
@@ -90,7 +73,12 @@ func (jsv *JSONSchemaValidator) Validate(schemaContent string, yamlContent []byt
 		return nil, fmt.Errorf("failed to convert schema to *jsonschema.Schema")
 	}
 
-	err := schema.Validate(jsonYamlContent)
+	return jsv.ValidateCompiledSchema(schema, jsonYamlContent)
+}
+
+// ValidateCompiledSchema validates an already unmarshalled resource against a schema returned by Compile
+func (jsv *JSONSchemaValidator) ValidateCompiledSchema(schema *jsonschema.Schema, jsonContent interface{}) ([]jsonschema.Detailed, error) {
+	err := schema.Validate(jsonContent)
 
 	if err != nil {
 		if validationError, ok := err.(*jsonschema.ValidationError); ok {
@@ -103,6 +91,48 @@ func (jsv *JSONSchemaValidator) Validate(schemaContent string, yamlContent []byt
 	return nil, nil
 }
 
+// Compile compiles a json schema with all of datree's custom keys registered
+func Compile(schemaContent string) (*jsonschema.Schema, error) {
+	return CompileWithInventory(schemaContent, nil)
+}
+
+// CompileWithInventory compiles a json schema whose cross resource rules see the resources of the inventory
+func CompileWithInventory(schemaContent string, inventory *extensions.Inventory) (*jsonschema.Schema, error) {
+	compiler := newCompiler(inventory)
+
+	if err := compiler.AddResource("schema.json", strings.NewReader(schemaContent)); err != nil {
+		return nil, err
+	}
+
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		// the schema url is resolved against the working directory, so it's left out of the error
+		if schemaError, ok := err.(*jsonschema.SchemaError); ok {
+			return nil, schemaError.Err
+		}
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+func newCompiler(inventory *extensions.Inventory) *jsonschema.Compiler {
+	compiler := jsonschema.NewCompiler()
+	//format is treated as annotation in draft-2019 onwards. it needs to be explicitly enabled by compiler.AssertFormat = true.
+	//see reference: https://github.com/santhosh-tekuri/jsonschema/issues/43
+	compiler.AssertFormat = true
+
+	compiler.RegisterExtension("resourceMinimum", resourceMinimum, resourceMinimumCompiler{})
+	compiler.RegisterExtension("resourceMaximum", resourceMaximum, resourceMaximumCompiler{})
+	compiler.RegisterExtension("customKeyRule81", extensions.CustomKeyRule81, extensions.CustomKeyRule81Compiler{})
+	compiler.RegisterExtension("customKeyRule89", extensions.CustomKeyRule89, extensions.CustomKeyRule89Compiler{})
+	compiler.RegisterExtension("customKeyRule101", extensions.CustomKeyRule101, extensions.CustomKeyRule101Compiler{})
+	compiler.RegisterExtension("customKeyRegoRule", extensions.CustomKeyRegoRule, extensions.CustomKeyRegoDefinitionCompiler{Inventory: inventory})
+	compiler.RegisterExtension("customKeyCELRule", extensions.CustomKeyCELRule, extensions.CustomKeyCELDefinitionCompiler{Inventory: inventory})
+
+	return compiler
+}
+
 func (resourceMinimumCompiler) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
 	if resourceMinimum, ok := m["resourceMinimum"]; ok {
 		resourceMinimumStr, validStr := resourceMinimum.(string)
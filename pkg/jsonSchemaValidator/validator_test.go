@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 
 	extensions "github.com/datreeio/datree/pkg/jsonSchemaValidator/extensions"
@@ -159,6 +160,25 @@ func TestValidateRegoDefinitionCustomKeyFail(t *testing.T) {
 	assert.Contains(t, errorsResult[0].Error, "do not match")
 }
 
+func TestValidateRegoDefinitionCustomKeyFailWithViolationMessages(t *testing.T) {
+	failResourceYamlFileContent, customRuleSchemaYamlFileContent :=
+		getResourceAndSchemaYamlContentsAsString(
+			regoYamlFilesPath+"/rule-pass.yaml",
+			regoYamlFilesPath+"/valid-rego-definition-violation-messages.json",
+		)
+
+	jsonSchemaValidator := New()
+
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(errorsResult))
+	assert.Equal(t, "pod security context is missing", errorsResult[0].Error)
+	assert.Equal(t, "", errorsResult[0].InstanceLocation)
+	assert.Equal(t, "container front-end uses the latest tag", errorsResult[1].Error)
+	assert.Equal(t, "/spec/template/spec/containers/0/image", errorsResult[1].InstanceLocation)
+}
+
 func TestValidateRegoDefinitionCustomKeyFailDueToRegoCompile(t *testing.T) {
 	failResourceYamlFileContent, customRuleSchemaYamlFileContent :=
 		getResourceAndSchemaYamlContentsAsString(
@@ -168,10 +188,52 @@ func TestValidateRegoDefinitionCustomKeyFailDueToRegoCompile(t *testing.T) {
 
 	jsonSchemaValidator := New()
 
-	errorsResult, _ := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
 
-	assert.GreaterOrEqual(t, len(errorsResult), 1)
-	assert.Contains(t, errorsResult[0].Error, "can't compile rego code, rego code must have a package")
+	assert.Empty(t, errorsResult)
+	assert.ErrorContains(t, err, "can't compile rego code, rego code must have a package")
+}
+
+func TestValidateRegoDefinitionCustomKeyFailDueToCompleteViolationRule(t *testing.T) {
+	resourceYamlFileContent, customRuleSchemaYamlFileContent :=
+		getResourceAndSchemaYamlContentsAsString(
+			regoYamlFilesPath+"/rule-fail.yaml",
+			regoYamlFilesPath+"/invalid-rego-definition-complete-violation-rule.json",
+		)
+
+	jsonSchemaValidator := New()
+
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, resourceYamlFileContent)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(errorsResult))
+	assert.Contains(t, errorsResult[0].Error, "violation needs to be a partial set rule")
+}
+
+func TestRegoDefinitionCustomKeyConcurrentValidation(t *testing.T) {
+	c := jsonschema.NewCompiler()
+	c.RegisterExtension(extensions.RegoDefinitionCustomKey, extensions.CustomKeyRegoRule, extensions.CustomKeyRegoDefinitionCompiler{})
+	if err := c.AddResource("test.json", strings.NewReader(validRegoDefinitionJson)); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := c.Compile("test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	passResource, _ := getInterfaceFromYamlContext(rulePass)
+	failResource, _ := getInterfaceFromYamlContext(ruleFail)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, schema.Validate(passResource))
+			assert.Error(t, schema.Validate(failResource))
+		}()
+	}
+	wg.Wait()
 }
 
 func TestCELDefinitionCustomKey(t *testing.T) {
@@ -240,6 +302,44 @@ func TestValidateCELDefinitionCustomKeyPass(t *testing.T) {
 	assert.Empty(t, errorsResult)
 }
 
+func TestValidateCELDefinitionCustomKeyPassWithKubernetesLibraries(t *testing.T) {
+	passResourceYamlFileContent, customRuleSchemaYamlFileContent :=
+		getResourceAndSchemaYamlContentsAsString(
+			regoYamlFilesPath+"/rule-pass.yaml",
+			regoYamlFilesPath+"/valid-cel-definition-kubernetes-libraries.json",
+		)
+
+	jsonSchemaValidator := New()
+
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, passResourceYamlFileContent)
+
+	assert.NoError(t, err)
+	assert.Empty(t, errorsResult)
+}
+
+func TestValidateCELDefinitionCustomKeyFailWithMessages(t *testing.T) {
+	failResourceYamlFileContent, customRuleSchemaYamlFileContent :=
+		getResourceAndSchemaYamlContentsAsString(
+			regoYamlFilesPath+"/rule-fail.yaml",
+			regoYamlFilesPath+"/valid-cel-definition-messages.json",
+		)
+
+	jsonSchemaValidator := New()
+
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
+
+	assert.NoError(t, err)
+	var failureMessages []string
+	for _, errorResult := range errorsResult {
+		failureMessages = append(failureMessages, errorResult.Error)
+	}
+	assert.Equal(t, []string{
+		"deployment labels should contain billing label",
+		"deployment rss-site should have at least 3 replicas (reason: Forbidden)",
+		"failed expression: object.metadata.name.startsWith('prod-')",
+	}, failureMessages)
+}
+
 func TestValidateCELDefinitionCustomKeyFailDueToCELCompile(t *testing.T) {
 	failResourceYamlFileContent, customRuleSchemaYamlFileContent :=
 		getResourceAndSchemaYamlContentsAsString(
@@ -249,10 +349,25 @@ func TestValidateCELDefinitionCustomKeyFailDueToCELCompile(t *testing.T) {
 
 	jsonSchemaValidator := New()
 
-	errorsResult, _ := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
 
-	assert.GreaterOrEqual(t, len(errorsResult), 1)
-	assert.Contains(t, errorsResult[0].Error, "cel expression compile error")
+	assert.Empty(t, errorsResult)
+	assert.ErrorContains(t, err, "cel expression compile error")
+}
+
+func TestValidateCELDefinitionCustomKeyFailDueToNonBooleanExpression(t *testing.T) {
+	failResourceYamlFileContent, customRuleSchemaYamlFileContent :=
+		getResourceAndSchemaYamlContentsAsString(
+			regoYamlFilesPath+"/rule-pass.yaml",
+			regoYamlFilesPath+"/invalid-cel-definition-output-type.json",
+		)
+
+	jsonSchemaValidator := New()
+
+	errorsResult, err := jsonSchemaValidator.ValidateYamlSchema(customRuleSchemaYamlFileContent, failResourceYamlFileContent)
+
+	assert.Empty(t, errorsResult)
+	assert.ErrorContains(t, err, "cel expression needs to return a boolean")
 }
 
 func getResourceAndSchemaYamlContentsAsString(resourceToValidatePath string, schemaPath string) (string, string) {
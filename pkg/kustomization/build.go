@@ -0,0 +1,122 @@
+package kustomization
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	LoadRestrictorRootOnly = "LoadRestrictionsRootOnly"
+	LoadRestrictorNone     = "LoadRestrictionsNone"
+)
+
+// BinRunner runs the kustomize or kubectl binary, it builds the kustomizations that fail to build in-process
+type BinRunner interface {
+	ExecuteKustomizeBin(args []string) ([]byte, error)
+}
+
+// BuildOptions are the options of kustomize build that are supported by the in-process build and the kustomize binary
+type BuildOptions struct {
+	EnableHelm         bool
+	LoadRestrictor     string
+	EnableAlphaPlugins bool
+}
+
+func (options *BuildOptions) Validate() error {
+	if options.LoadRestrictor != "" && options.LoadRestrictor != LoadRestrictorRootOnly && options.LoadRestrictor != LoadRestrictorNone {
+		return fmt.Errorf("invalid load restrictor %s, valid values are %s, %s", options.LoadRestrictor, LoadRestrictorRootOnly, LoadRestrictorNone)
+	}
+	return nil
+}
+
+// Args returns the flags of kustomize build for the options
+func (options *BuildOptions) Args() []string {
+	var args []string
+	if options.EnableHelm {
+		args = append(args, "--enable-helm")
+	}
+	if options.LoadRestrictor == LoadRestrictorNone {
+		args = append(args, "--load-restrictor", LoadRestrictorNone)
+	}
+	if options.EnableAlphaPlugins {
+		args = append(args, "--enable-alpha-plugins")
+	}
+	return args
+}
+
+func (options *BuildOptions) krustyOptions() *krusty.Options {
+	krustyOptions := krusty.MakeDefaultOptions()
+	if options.LoadRestrictor == LoadRestrictorNone {
+		krustyOptions.LoadRestrictions = types.LoadRestrictionsNone
+	}
+	// the alpha plugins enable helm too, like they do in kustomize build
+	if options.EnableAlphaPlugins {
+		krustyOptions.PluginConfig = types.EnabledPluginConfig(types.BploLoadFromFileSys)
+	} else {
+		krustyOptions.PluginConfig.HelmConfig.Enabled = options.EnableHelm
+	}
+	krustyOptions.PluginConfig.HelmConfig.Command = "helm"
+	return krustyOptions
+}
+
+// Build builds the kustomization in-process, like kustomize build does. The kustomization is a local directory or a remote URL
+func Build(kustomization string, options *BuildOptions) ([]byte, error) {
+	resources, err := krusty.MakeKustomizer(options.krustyOptions()).Run(filesys.MakeFsOnDisk(), kustomization)
+	if err != nil {
+		return nil, fmt.Errorf("kustomize build errored: %s", err.Error())
+	}
+	return resources.AsYaml()
+}
+
+// BuildWithSources builds the kustomization with the sources of the resources set, so the results cite the bases and overlays files.
+// A local kustomization directory is built with origin annotations, when the build with origin annotations fails it's built as is
+func BuildWithSources(binRunner BinRunner, args []string, options *BuildOptions) ([]byte, error) {
+	var root string
+	if len(args) == 1 {
+		if fileInfo, err := os.Stat(args[0]); err == nil && fileInfo.IsDir() {
+			root, _ = filepath.Abs(args[0])
+		}
+	}
+
+	if root != "" {
+		originsDir, err := NewOriginsKustomization(root)
+		if err == nil {
+			defer os.RemoveAll(originsDir)
+			out, err := buildWithFallback(binRunner, []string{originsDir}, options)
+			if err == nil {
+				return AddOriginSources(out, originsDir)
+			}
+		}
+	}
+
+	out, err := buildWithFallback(binRunner, args, options)
+	if err != nil {
+		return nil, err
+	}
+	return AddOriginSources(out, root)
+}
+
+// buildWithFallback builds the kustomization in-process, and with the kustomize or kubectl binary when the in-process build fails.
+// The error of the in-process build is returned when both fail, so a missing binary isn't reported instead of the kustomization error
+func buildWithFallback(binRunner BinRunner, args []string, options *BuildOptions) ([]byte, error) {
+	binArgs := append(append([]string{}, args...), options.Args()...)
+	if len(args) != 1 {
+		return binRunner.ExecuteKustomizeBin(binArgs)
+	}
+
+	out, err := Build(args[0], options)
+	if err == nil {
+		return out, nil
+	}
+
+	binOut, binErr := binRunner.ExecuteKustomizeBin(binArgs)
+	if binErr != nil {
+		return nil, err
+	}
+	return binOut, nil
+}
@@ -0,0 +1,132 @@
+package kustomization
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type binRunnerMock struct {
+	mock.Mock
+}
+
+func (m *binRunnerMock) ExecuteKustomizeBin(args []string) ([]byte, error) {
+	arguments := m.Called(args)
+	return arguments.Get(0).([]byte), arguments.Error(1)
+}
+
+func TestBuildOptionsArgs(t *testing.T) {
+	assert.Nil(t, (&BuildOptions{LoadRestrictor: LoadRestrictorRootOnly}).Args())
+	assert.Equal(t, []string{"--enable-helm", "--load-restrictor", "LoadRestrictionsNone", "--enable-alpha-plugins"}, (&BuildOptions{
+		EnableHelm:         true,
+		LoadRestrictor:     LoadRestrictorNone,
+		EnableAlphaPlugins: true,
+	}).Args())
+}
+
+func TestBuildOptionsValidate(t *testing.T) {
+	assert.Nil(t, (&BuildOptions{}).Validate())
+	assert.Nil(t, (&BuildOptions{LoadRestrictor: LoadRestrictorNone}).Validate())
+	assert.EqualError(t, (&BuildOptions{LoadRestrictor: "none"}).Validate(), "invalid load restrictor none, valid values are LoadRestrictionsRootOnly, LoadRestrictionsNone")
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("should build an overlay with its bases", func(t *testing.T) {
+		out, err := Build("test_fixtures/kustomize/overlays/production", &BuildOptions{})
+		assert.Nil(t, err)
+		assert.Contains(t, string(out), "kind: Deployment")
+		assert.Contains(t, string(out), "kind: Service")
+		assert.Contains(t, string(out), "name: rss-config-")
+	})
+
+	t.Run("should pass the load restrictor to the build", func(t *testing.T) {
+		_, err := Build("test_fixtures/kustomize/overlays/shared", &BuildOptions{LoadRestrictor: LoadRestrictorRootOnly})
+		assert.ErrorContains(t, err, "kustomize build errored: ")
+
+		out, err := Build("test_fixtures/kustomize/overlays/shared", &BuildOptions{LoadRestrictor: LoadRestrictorNone})
+		assert.Nil(t, err)
+		assert.Contains(t, string(out), "kind: Service")
+	})
+
+	t.Run("should build the origins kustomization with the files of the resources", func(t *testing.T) {
+		originsDir, err := NewOriginsKustomization("test_fixtures/kustomize/overlays/production")
+		assert.Nil(t, err)
+		defer os.RemoveAll(originsDir)
+
+		out, err := Build(originsDir, &BuildOptions{})
+		assert.Nil(t, err)
+		out, err = AddOriginSources(out, originsDir)
+		assert.Nil(t, err)
+
+		fixturesDir, err := filepath.Abs("test_fixtures/kustomize")
+		assert.Nil(t, err)
+		assert.Contains(t, string(out), "# Source: "+filepath.Join(fixturesDir, "base/deployment.yaml")+"\napiVersion: apps/v1\n")
+		assert.Contains(t, string(out), "# Source: "+filepath.Join(fixturesDir, "overlays/production/service.yaml")+"\napiVersion: v1\n")
+		assert.Contains(t, string(out), "# Source: "+filepath.Join(fixturesDir, "overlays/production/kustomization.yaml")+"\napiVersion: v1\n")
+		assert.NotContains(t, string(out), OriginAnnotation)
+	})
+}
+
+func TestBuildWithSources(t *testing.T) {
+	t.Run("should build a kustomization in-process", func(t *testing.T) {
+		commandRunner := &binRunnerMock{}
+
+		out, err := BuildWithSources(commandRunner, []string{"test_fixtures/kustomize/overlays/production"}, &BuildOptions{})
+
+		assert.Nil(t, err)
+		assert.Contains(t, string(out), "/test_fixtures/kustomize/base/deployment.yaml\napiVersion: apps/v1\n")
+		commandRunner.AssertNotCalled(t, "ExecuteKustomizeBin", mock.Anything)
+	})
+
+	t.Run("should build with the kustomize binary and its build flags when the in-process build fails", func(t *testing.T) {
+		kustomizationDir := t.TempDir()
+		commandRunner := &binRunnerMock{}
+		commandRunner.On("ExecuteKustomizeBin", mock.MatchedBy(func(args []string) bool {
+			return len(args) == 2 && args[1] == "--enable-helm"
+		})).Return([]byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: rss\n"), nil)
+
+		out, err := BuildWithSources(commandRunner, []string{kustomizationDir}, &BuildOptions{EnableHelm: true})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: rss\n", string(out))
+	})
+
+	t.Run("should return the in-process build error when the kustomize binary fails too", func(t *testing.T) {
+		commandRunner := &binRunnerMock{}
+		commandRunner.On("ExecuteKustomizeBin", mock.Anything).Return([]byte(nil), errors.New("kubectl or kustomize is not installed"))
+
+		_, err := BuildWithSources(commandRunner, []string{"test_fixtures/kustomize/overlays/shared"}, &BuildOptions{})
+
+		assert.ErrorContains(t, err, "kustomize build errored: ")
+	})
+
+	t.Run("should build a kustomization directory with the sources of its resources", func(t *testing.T) {
+		kustomizationDir := t.TempDir()
+		commandRunner := &binRunnerMock{}
+		commandRunner.On("ExecuteKustomizeBin", mock.Anything).Return([]byte("apiVersion: v1\nkind: Service\nmetadata:\n  annotations:\n    config.kubernetes.io/origin: |\n      path: /base/service.yaml\n  name: rss\n"), nil)
+
+		out, err := BuildWithSources(commandRunner, []string{kustomizationDir}, &BuildOptions{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "---\n# Source: /base/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: rss\n", string(out))
+		commandRunner.AssertNumberOfCalls(t, "ExecuteKustomizeBin", 1)
+		commandRunner.AssertNotCalled(t, "ExecuteKustomizeBin", []string{kustomizationDir})
+	})
+
+	t.Run("should build the kustomization as is when the build with origin annotations fails", func(t *testing.T) {
+		kustomizationDir := t.TempDir()
+		commandRunner := &binRunnerMock{}
+		commandRunner.On("ExecuteKustomizeBin", []string{kustomizationDir}).Return([]byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: rss\n"), nil)
+		commandRunner.On("ExecuteKustomizeBin", mock.Anything).Return([]byte(nil), errors.New("kustomize build errored"))
+
+		out, err := BuildWithSources(commandRunner, []string{kustomizationDir}, &BuildOptions{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: rss\n", string(out))
+		commandRunner.AssertNumberOfCalls(t, "ExecuteKustomizeBin", 2)
+	})
+}
@@ -0,0 +1,159 @@
+package kustomization
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OriginAnnotation is set by kustomize on the built resources when the kustomization has buildMetadata: [originAnnotations]
+const OriginAnnotation = "config.kubernetes.io/origin"
+
+// Origin is the value of the origin annotation, generated resources have configuredIn instead of path
+type Origin struct {
+	Path         string `yaml:"path"`
+	Repo         string `yaml:"repo"`
+	Ref          string `yaml:"ref"`
+	ConfiguredIn string `yaml:"configuredIn"`
+}
+
+// NewOriginsKustomization writes a kustomization that builds the kustomization directory with origin annotations and returns its directory.
+// The origins of the resources it builds are relative to the returned directory
+func NewOriginsKustomization(kustomizationDir string) (string, error) {
+	absoluteKustomizationDir, err := filepath.Abs(kustomizationDir)
+	if err != nil {
+		return "", err
+	}
+
+	originsDir, err := os.MkdirTemp("", "datree_kustomize_origins_")
+	if err != nil {
+		return "", err
+	}
+
+	// kustomize doesn't accept absolute paths of kustomization directories
+	relativeKustomizationDir, err := filepath.Rel(originsDir, absoluteKustomizationDir)
+	if err != nil {
+		os.RemoveAll(originsDir)
+		return "", err
+	}
+
+	content := fmt.Sprintf("resources:\n  - %s\nbuildMetadata:\n  - originAnnotations\n", filepath.ToSlash(relativeKustomizationDir))
+	err = os.WriteFile(filepath.Join(originsDir, "kustomization.yaml"), []byte(content), 0600)
+	if err != nil {
+		os.RemoveAll(originsDir)
+		return "", err
+	}
+	return originsDir, nil
+}
+
+// AddOriginSources replaces the origin annotations of the built resources with `# Source:` comments of the files they were built from,
+// so the results cite the files of the bases and overlays. The local paths of the origins are relative to the root
+func AddOriginSources(built []byte, root string) ([]byte, error) {
+	var output bytes.Buffer
+	decoder := yaml.NewDecoder(bytes.NewReader(built))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+
+		origin, err := removeOriginAnnotation(document.Content[0])
+		if err != nil {
+			return nil, err
+		}
+
+		output.WriteString("---\n")
+		if origin != nil {
+			if source := getOriginSource(origin, root); source != "" {
+				output.WriteString(fmt.Sprintf("# Source: %s\n", source))
+			}
+		}
+
+		encoder := yaml.NewEncoder(&output)
+		encoder.SetIndent(2)
+		err = encoder.Encode(&document)
+		if err != nil {
+			return nil, err
+		}
+		encoder.Close()
+	}
+	return output.Bytes(), nil
+}
+
+// removeOriginAnnotation removes the origin annotation of the resource and returns its origin, or nil when it has no origin annotation
+func removeOriginAnnotation(resource *yaml.Node) (*Origin, error) {
+	metadata := getMappingValue(resource, "metadata")
+	annotations := getMappingValue(metadata, "annotations")
+	originValue := getMappingValue(annotations, OriginAnnotation)
+	if originValue == nil {
+		return nil, nil
+	}
+
+	var origin Origin
+	err := yaml.Unmarshal([]byte(originValue.Value), &origin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %s", OriginAnnotation, err.Error())
+	}
+
+	removeMappingKey(annotations, OriginAnnotation)
+	if len(annotations.Content) == 0 {
+		removeMappingKey(metadata, "annotations")
+	}
+	return &origin, nil
+}
+
+// getOriginSource returns the file of the origin, remote files are cited by their repo
+func getOriginSource(origin *Origin, root string) string {
+	path := origin.Path
+	if path == "" {
+		path = origin.ConfiguredIn
+	}
+	if path == "" {
+		return ""
+	}
+
+	if origin.Repo != "" {
+		source := origin.Repo + "/" + path
+		if origin.Ref != "" {
+			source += "?ref=" + origin.Ref
+		}
+		return source
+	}
+
+	if filepath.IsAbs(path) || root == "" {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(root, path)
+}
+
+func getMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key {
+			return node.Content[index+1]
+		}
+	}
+	return nil
+}
+
+func removeMappingKey(node *yaml.Node, key string) {
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key {
+			node.Content = append(node.Content[:index], node.Content[index+2:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package kustomization
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed test_fixtures/builtWithOrigins.yaml
+var builtWithOrigins string
+
+func TestAddOriginSources(t *testing.T) {
+	out, err := AddOriginSources([]byte(builtWithOrigins), "/home/user/build")
+	assert.Nil(t, err)
+	assert.Equal(t, `---
+# Source: /home/user/app/overlays/prod/kustomization.yaml
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: rss-config-5c9b5g2hcg
+---
+# Source: /home/user/app/overlays/prod/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    team: rss
+  name: rss
+spec:
+  ports:
+    - port: 80
+---
+# Source: https://github.com/kubernetes-sigs/kustomize/examples/helloWorld/deployment.yaml?ref=v1.0.6
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: the-deployment
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: rss
+`, string(out))
+}
+
+func TestNewOriginsKustomization(t *testing.T) {
+	kustomizationDir := t.TempDir()
+
+	originsDir, err := NewOriginsKustomization(kustomizationDir)
+	assert.Nil(t, err)
+	defer os.RemoveAll(originsDir)
+
+	relativeKustomizationDir, err := filepath.Rel(originsDir, kustomizationDir)
+	assert.Nil(t, err)
+	content, err := os.ReadFile(filepath.Join(originsDir, "kustomization.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "resources:\n  - "+filepath.ToSlash(relativeKustomizationDir)+"\nbuildMetadata:\n  - originAnnotations\n", string(content))
+}
@@ -20,6 +20,7 @@ type LocalConfig struct {
 	Offline         string
 	PolicyConfig    string
 	SchemaLocations []string
+	Exceptions      string
 }
 
 type TokenClient interface {
@@ -45,6 +46,7 @@ const (
 	offlineKey         = "offline"
 	policyConfigKey    = "policy_config"
 	schemaLocationsKey = "schema_locations"
+	exceptionsKey      = "exceptions"
 )
 
 func (lc *LocalConfigClient) GetLocalConfiguration() (*LocalConfig, error) {
@@ -62,6 +64,7 @@ func (lc *LocalConfigClient) GetLocalConfiguration() (*LocalConfig, error) {
 	offline := viper.GetString(offlineKey)
 	policyConfig := viper.GetString(policyConfigKey)
 	schemaLocations := viper.GetStringSlice(schemaLocationsKey)
+	exceptions := viper.GetString(exceptionsKey)
 
 	if offline == "" {
 		offline = "fail"
@@ -94,7 +97,7 @@ func (lc *LocalConfigClient) GetLocalConfiguration() (*LocalConfig, error) {
 		}
 	}
 
-	return &LocalConfig{Token: token, ClientId: clientId, SchemaVersion: schemaVersion, Offline: offline, PolicyConfig: policyConfig, SchemaLocations: schemaLocations}, nil
+	return &LocalConfig{Token: token, ClientId: clientId, SchemaVersion: schemaVersion, Offline: offline, PolicyConfig: policyConfig, SchemaLocations: schemaLocations, Exceptions: exceptions}, nil
 }
 
 func (lc *LocalConfigClient) Set(key string, value string) error {
@@ -108,9 +111,9 @@ func (lc *LocalConfigClient) Set(key string, value string) error {
 		return err
 	}
 
-	if key == policyConfigKey {
+	if key == policyConfigKey || key == exceptionsKey {
 		absPath, _ := filepath.Abs(value)
-		viper.Set(policyConfigKey, absPath)
+		viper.Set(key, absPath)
 	} else if key == schemaLocationsKey {
 		viper.Set(schemaLocationsKey, strings.Split(value, ","))
 	} else {
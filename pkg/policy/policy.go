@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"github.com/datreeio/datree/pkg/admissionPolicies"
 	"github.com/datreeio/datree/pkg/defaultPolicies"
 	"github.com/datreeio/datree/pkg/validatePoliciesYaml"
 
@@ -22,6 +23,11 @@ func GetPoliciesFileFromPath(path string) (*defaultPolicies.EvaluationPrerunPoli
 		return nil, err
 	}
 
+	policiesStrBytes, err = admissionPolicies.MergeIntoPoliciesYaml(policiesStrBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	var policies *defaultPolicies.EvaluationPrerunPolicies
 	policiesBytes, err := yaml.YAMLToJSON(policiesStrBytes)
 	if err != nil {
@@ -33,6 +33,7 @@ type FailedRule struct {
 	Suggestion         string
 	DocumentationUrl   string
 	PACIdentifier      string
+	Severity           string
 	OccurrencesDetails []OccurrenceDetails
 }
 
@@ -42,6 +43,10 @@ type OccurrenceDetails struct {
 	SkipMessage               string
 	FailureLocations          []cliClient.FailureLocation
 	ValidationFailureMessages []string
+	// DocumentIndex is set when the resource was rendered from a source, like a helm chart template
+	DocumentIndex *int
+	// RenderedFrom is set when the resource was rendered from a helm chart or kustomization directory by the render mode
+	RenderedFrom string
 }
 
 type InvalidYamlInfo struct {
@@ -62,6 +67,7 @@ type Warning struct {
 	Title           string
 	FailedRules     []FailedRule
 	SkippedRules    []FailedRule
+	WarningRules    []FailedRule
 	InvalidYamlInfo InvalidYamlInfo
 	InvalidK8sInfo  InvalidK8sInfo
 	ExtraMessages   []ExtraMessage
@@ -189,7 +195,7 @@ func (p *Printer) GetWarningsText(warnings []Warning, quiet bool) string {
 					}
 
 					for _, occurrenceDetails := range skippedRule.OccurrencesDetails {
-						sb.WriteString(fmt.Sprintf("    - metadata.name: %v (kind: %v)\n", p.getStringOrNotAvailableText(occurrenceDetails.MetadataName), p.getStringOrNotAvailableText(occurrenceDetails.Kind)))
+						sb.WriteString(p.getOccurrenceText(occurrenceDetails))
 						m := p.Theme.Colors.Highlight.Sprint(occurrenceDetails.SkipMessage)
 						sb.WriteString(fmt.Sprintf("%v %v\n", p.Theme.Emoji.Suggestion, m))
 					}
@@ -199,58 +205,91 @@ func (p *Printer) GetWarningsText(warnings []Warning, quiet bool) string {
 			}
 
 			for _, failedRule := range warning.FailedRules {
-				var occurrencesPostfix string
+				sb.WriteString(p.getFailedRuleText(failedRule, p.Theme.Emoji.Error, p.Theme.Colors.RedBold))
+			}
 
-				if failedRule.Occurrences > 1 {
-					occurrencesPostfix = "s"
-				} else {
-					occurrencesPostfix = ""
-				}
-				formattedOccurrences := fmt.Sprintf(" [%d occurrence%v]", failedRule.Occurrences, occurrencesPostfix)
-				occurrences := p.Theme.Colors.Highlight.Sprintf(formattedOccurrences)
+			if len(warning.WarningRules) > 0 {
+				sb.WriteString(fmt.Sprintf("%v", p.Theme.Colors.Yellow.Sprintf("WARNINGS")+"\n\n"))
+			}
 
-				ruleName := p.Theme.Colors.RedBold.Sprint(failedRule.Name)
+			for _, warningRule := range warning.WarningRules {
+				sb.WriteString(p.getFailedRuleText(warningRule, p.Theme.Emoji.Warning, p.Theme.Colors.Yellow))
+			}
+		}
+	}
 
-				sb.WriteString(fmt.Sprintf("%v %v %v\n", p.Theme.Emoji.Error, ruleName, occurrences))
+	sb.WriteString("\n")
+	return sb.String()
+}
 
-				if failedRule.PACIdentifier != "" {
-					PACIdentifier := p.Theme.Colors.Cyan.Sprint(failedRule.PACIdentifier)
-					sb.WriteString(fmt.Sprintf("    Policy as code identifier: %v\n", PACIdentifier))
-				}
+// getFailedRuleText renders a rule with its failing occurrences, failed rules and warnings differ only in the emoji and color
+func (p *Printer) getFailedRuleText(failedRule FailedRule, ruleEmoji string, ruleColor *color.Color) string {
+	var sb strings.Builder
+	var occurrencesPostfix string
 
-				if failedRule.DocumentationUrl != "" {
-					howToFix := p.Theme.Colors.Cyan.Sprint(failedRule.DocumentationUrl)
-					sb.WriteString(fmt.Sprintf("    How to fix: %v\n", howToFix))
-				}
+	if failedRule.Occurrences > 1 {
+		occurrencesPostfix = "s"
+	} else {
+		occurrencesPostfix = ""
+	}
+	formattedOccurrences := fmt.Sprintf(" [%d occurrence%v]", failedRule.Occurrences, occurrencesPostfix)
+	occurrences := p.Theme.Colors.Highlight.Sprintf(formattedOccurrences)
 
-				for _, occurrenceDetails := range failedRule.OccurrencesDetails {
-					sb.WriteString(fmt.Sprintf("    - metadata.name: %v (kind: %v)\n", p.getStringOrNotAvailableText(occurrenceDetails.MetadataName), p.getStringOrNotAvailableText(occurrenceDetails.Kind)))
-					for _, validationResult := range occurrenceDetails.FailureLocations {
-						if validationResult.SchemaPath != "" {
-							failurePath := fmt.Sprintf("%v (line: %d:%d)\n", strings.Replace(validationResult.SchemaPath, "/", ".", -1)[1:], validationResult.FailedErrorLine, validationResult.FailedErrorColumn)
-							sb.WriteString(fmt.Sprintf("      > key: %v", failurePath))
-						}
-					}
-					sb.WriteString("\n")
-					if len(occurrenceDetails.ValidationFailureMessages) > 0 {
-						for _, validationFailureMessage := range occurrenceDetails.ValidationFailureMessages {
-							sb.WriteString(validationFailureMessage)
-						}
-						sb.WriteString("\n")
-					}
+	ruleName := ruleColor.Sprint(failedRule.Name)
 
-				}
-				sb.WriteString(fmt.Sprintf("%v %v\n", p.Theme.Emoji.Suggestion, failedRule.Suggestion))
+	sb.WriteString(fmt.Sprintf("%v %v %v\n", ruleEmoji, ruleName, occurrences))
 
-				sb.WriteString("\n")
+	if failedRule.Severity != "" {
+		sb.WriteString(fmt.Sprintf("    Severity: %v\n", ruleColor.Sprint(failedRule.Severity)))
+	}
+
+	if failedRule.PACIdentifier != "" {
+		PACIdentifier := p.Theme.Colors.Cyan.Sprint(failedRule.PACIdentifier)
+		sb.WriteString(fmt.Sprintf("    Policy as code identifier: %v\n", PACIdentifier))
+	}
+
+	if failedRule.DocumentationUrl != "" {
+		howToFix := p.Theme.Colors.Cyan.Sprint(failedRule.DocumentationUrl)
+		sb.WriteString(fmt.Sprintf("    How to fix: %v\n", howToFix))
+	}
+
+	for _, occurrenceDetails := range failedRule.OccurrencesDetails {
+		sb.WriteString(p.getOccurrenceText(occurrenceDetails))
+		// the lines of a rendered resource are the lines of the rendered output, not of its source
+		lineTitle := "line"
+		if occurrenceDetails.DocumentIndex != nil {
+			lineTitle = "rendered line"
+		}
+		for _, validationResult := range occurrenceDetails.FailureLocations {
+			if validationResult.SchemaPath != "" {
+				failurePath := fmt.Sprintf("%v (%v: %d:%d)\n", strings.Replace(validationResult.SchemaPath, "/", ".", -1)[1:], lineTitle, validationResult.FailedErrorLine, validationResult.FailedErrorColumn)
+				sb.WriteString(fmt.Sprintf("      > key: %v", failurePath))
 			}
 		}
+		sb.WriteString("\n")
+		for _, validationFailureMessage := range occurrenceDetails.ValidationFailureMessages {
+			sb.WriteString(validationFailureMessage + "\n")
+		}
+
 	}
+	sb.WriteString(fmt.Sprintf("%v %v\n", p.Theme.Emoji.Suggestion, failedRule.Suggestion))
 
 	sb.WriteString("\n")
 	return sb.String()
 }
 
+// getOccurrenceText returns the title of an occurrence, a rendered resource is cited by its document in its source
+func (p *Printer) getOccurrenceText(occurrenceDetails OccurrenceDetails) string {
+	resourceDetails := fmt.Sprintf("kind: %v", p.getStringOrNotAvailableText(occurrenceDetails.Kind))
+	if occurrenceDetails.DocumentIndex != nil {
+		resourceDetails += fmt.Sprintf(", document: %d", *occurrenceDetails.DocumentIndex)
+	}
+	if occurrenceDetails.RenderedFrom != "" {
+		resourceDetails += fmt.Sprintf(", rendered from: %v", occurrenceDetails.RenderedFrom)
+	}
+	return fmt.Sprintf("    - metadata.name: %v (%v)\n", p.getStringOrNotAvailableText(occurrenceDetails.MetadataName), resourceDetails)
+}
+
 type SummaryItem struct {
 	RightCol string
 	LeftCol  string
@@ -271,6 +310,8 @@ type EvaluationSummary struct {
 	PassedYamlValidationCount int
 	K8sValidation             string
 	PassedPolicyCheckCount    int
+	// PolicyCheckFilesCount is the count of files of the policy check, the sources of a rendered file are counted instead of the file
+	PolicyCheckFilesCount int
 }
 
 func (p *Printer) GetTitleText(title string) string {
@@ -281,6 +322,15 @@ func GetFileNameText(title string) string {
 	return fmt.Sprintf(">>  File: %s\n\n", title)
 }
 
+// GetPolicyTitleText is the title of the results of a policy in a run that evaluates several policies
+func GetPolicyTitleText(policyName string, isGating bool) string {
+	title := fmt.Sprintf("====  Policy: %s", policyName)
+	if isGating {
+		title += " (gating)"
+	}
+	return title + "\n\n"
+}
+
 func (p *Printer) GetEvaluationSummaryText(summary EvaluationSummary, k8sVersion string) string {
 	var sb strings.Builder
 	sb.WriteString(p.GetTextInColor("(Summary)\n\n", p.Theme.Colors.Highlight))
@@ -288,7 +338,11 @@ func (p *Printer) GetEvaluationSummaryText(summary EvaluationSummary, k8sVersion
 	sb.WriteString(p.GetYamlValidationSummaryText(summary.PassedYamlValidationCount, summary.FilesCount))
 
 	sb.WriteString(fmt.Sprintf("- Passing Kubernetes (%s) schema validation: %s\n\n", k8sVersion, summary.K8sValidation))
-	sb.WriteString(fmt.Sprintf("- Passing policy check: %v/%v\n\n", summary.PassedPolicyCheckCount, summary.FilesCount))
+	policyCheckFilesCount := summary.FilesCount
+	if summary.PolicyCheckFilesCount != 0 {
+		policyCheckFilesCount = summary.PolicyCheckFilesCount
+	}
+	sb.WriteString(fmt.Sprintf("- Passing policy check: %v/%v\n\n", summary.PassedPolicyCheckCount, policyCheckFilesCount))
 	return sb.String()
 }
 
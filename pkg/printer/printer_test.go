@@ -213,6 +213,38 @@ https://github.com/datreeio/helm-datree
 	})
 }
 
+func TestGetWarningsTextOfRenderedResource(t *testing.T) {
+	printer := CreateNewPrinter()
+	documentIndex := 1
+	warnings := []Warning{{
+		Title: GetFileNameText("web/templates/deployment.yaml"),
+		FailedRules: []FailedRule{{
+			Name:        "Caption",
+			Occurrences: 1,
+			Suggestion:  "Suggestion",
+			OccurrencesDetails: []OccurrenceDetails{{
+				MetadataName:  "worker",
+				Kind:          "Deployment",
+				DocumentIndex: &documentIndex,
+				FailureLocations: []cliClient.FailureLocation{{
+					SchemaPath:        ".spec.template.spec.containers.0.image",
+					FailedErrorLine:   30,
+					FailedErrorColumn: 18,
+				}},
+			}},
+		}},
+	}}
+
+	got := printer.GetWarningsText(warnings, false)
+
+	assert.Contains(t, got, "    - metadata.name: worker (kind: Deployment, document: 1)\n      > key: spec.template.spec.containers.0.image (rendered line: 30:18)\n")
+
+	warnings[0].FailedRules[0].OccurrencesDetails[0].RenderedFrom = "deploy/charts/web"
+	got = printer.GetWarningsText(warnings, false)
+
+	assert.Contains(t, got, "    - metadata.name: worker (kind: Deployment, document: 1, rendered from: deploy/charts/web)\n")
+}
+
 func TestGetEvaluationSummaryText(t *testing.T) {
 	t.Run("Test GetEvaluationSummaryText", func(t *testing.T) {
 		StdOut = new(bytes.Buffer)
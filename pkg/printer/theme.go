@@ -30,6 +30,7 @@ type Theme struct {
 		Error      string
 		Suggestion string
 		Skip       string
+		Warning    string
 	}
 }
 
@@ -69,10 +70,12 @@ func createDefaultTheme() *Theme {
 			Error      string
 			Suggestion string
 			Skip       string
+			Warning    string
 		}{
 			Error:      emoji.Sprint(":cross_mark:"),
 			Suggestion: emoji.Sprint(":light_bulb:"),
 			Skip:       emoji.Sprint(":fast_forward:"),
+			Warning:    emoji.Sprint(":warning:"),
 		},
 	}
 }
@@ -112,10 +115,12 @@ func CreateSimpleTheme() *Theme {
 			Error      string
 			Suggestion string
 			Skip       string
+			Warning    string
 		}{
 			Error:      "[X] ",
 			Suggestion: "[*] ",
 			Skip:       "[>>]",
+			Warning:    "[!] ",
 		},
 	}
 }
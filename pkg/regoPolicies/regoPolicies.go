@@ -0,0 +1,182 @@
+// This package loads a directory of conftest style rego policies as rules that run next to the selected policy
+
+package regoPolicies
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	policy_factory "github.com/datreeio/datree/bl/policy"
+	"github.com/open-policy-agent/opa/ast"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	denyLevel = "deny"
+	warnLevel = "warn"
+)
+
+// conftest rules are named deny, violation or warn, optionally with a suffix (deny_privileged)
+var conftestRuleNameRegex = regexp.MustCompile(`^(deny|violation|warn)(_[A-Za-z0-9_]+)?$`)
+
+// regoDirRegoCode collects the messages of the conftest rules as violations of a datree rego rule
+const regoDirRegoCode = `package datree.rego_dir
+%s`
+
+const regoDirViolationCode = `
+violation[result] {
+	result := %s[_]
+}
+`
+
+type regoPackage struct {
+	path      ast.Ref
+	ruleNames map[string][]string
+}
+
+// LoadRules returns a rule for the deny and violation rules of each package in the directory, and a warning rule for its warn rules.
+// All the modules in the directory are loaded with every rule, so policies can use shared libs
+func LoadRules(dir string) ([]policy_factory.RuleWithSchema, error) {
+	modules, codes, err := readModules(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rego policies from %s: %s", dir, err.Error())
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no rego policies found in %s", dir)
+	}
+
+	var libs []interface{}
+	for _, code := range codes {
+		libs = append(libs, code)
+	}
+
+	packages := getRegoPackages(modules)
+
+	var rules []policy_factory.RuleWithSchema
+	for _, regoPackage := range packages {
+		packageName := strings.TrimPrefix(regoPackage.path.String(), "data.")
+
+		for _, level := range []string{denyLevel, warnLevel} {
+			ruleNames := regoPackage.ruleNames[level]
+			if len(ruleNames) == 0 {
+				continue
+			}
+
+			var violationsCode strings.Builder
+			for _, ruleName := range ruleNames {
+				violationsCode.WriteString(fmt.Sprintf(regoDirViolationCode, regoPackage.path.Append(ast.StringTerm(ruleName)).String()))
+			}
+
+			rule := policy_factory.RuleWithSchema{
+				RuleIdentifier: getRuleIdentifier(packageName, level),
+				RuleName:       packageName + " " + level + " [REGO POLICY]",
+				Schema: map[string]interface{}{
+					"regoDefinition": map[string]interface{}{
+						"libs": libs,
+						"code": fmt.Sprintf(regoDirRegoCode, violationsCode.String()),
+					},
+				},
+				MessageOnFailure: "Denied by the rego policies in package " + packageName,
+				Severity:         policy_factory.DefaultSeverity,
+			}
+			if level == warnLevel {
+				rule.MessageOnFailure = "Warning from the rego policies in package " + packageName
+				rule.IsWarning = true
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// readModules parses the .rego files in the directory and its subdirectories, rego unit tests are ignored
+func readModules(dir string) ([]*ast.Module, []string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && filepath.Ext(path) == ".rego" && !strings.HasSuffix(path, "_test.rego") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(paths)
+
+	var modules []*ast.Module
+	var codes []string
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		module, err := ast.ParseModule(path, string(content))
+		if err != nil {
+			return nil, nil, err
+		}
+		if module == nil {
+			continue
+		}
+
+		modules = append(modules, module)
+		codes = append(codes, string(content))
+	}
+	return modules, codes, nil
+}
+
+// getRegoPackages returns the packages that have conftest rules, in the order they were found.
+// deny and violation rules are both failures
+func getRegoPackages(modules []*ast.Module) []*regoPackage {
+	var packages []*regoPackage
+	packagesByPath := make(map[string]*regoPackage)
+	for _, module := range modules {
+		packagePath := module.Package.Path.String()
+		for _, rule := range module.Rules {
+			if len(rule.Head.Args) > 0 {
+				continue
+			}
+			ruleName, ok := rule.Head.Ref()[0].Value.(ast.Var)
+			if !ok {
+				continue
+			}
+			match := conftestRuleNameRegex.FindStringSubmatch(string(ruleName))
+			if match == nil {
+				continue
+			}
+
+			level := denyLevel
+			if match[1] == warnLevel {
+				level = warnLevel
+			}
+
+			packageRules, ok := packagesByPath[packagePath]
+			if !ok {
+				packageRules = &regoPackage{path: module.Package.Path, ruleNames: make(map[string][]string)}
+				packagesByPath[packagePath] = packageRules
+				packages = append(packages, packageRules)
+			}
+			if !slices.Contains(packageRules.ruleNames[level], string(ruleName)) {
+				packageRules.ruleNames[level] = append(packageRules.ruleNames[level], string(ruleName))
+			}
+		}
+	}
+	return packages
+}
+
+var nonIdentifierCharacters = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// getRuleIdentifier converts a package and a level (kubernetes.admission, deny) to a rule identifier (REGO_KUBERNETES_ADMISSION_DENY)
+func getRuleIdentifier(packageName string, level string) string {
+	identifier := nonIdentifierCharacters.ReplaceAllString(packageName, "_")
+	return strings.ToUpper("REGO_" + strings.Trim(identifier, "_") + "_" + level)
+}
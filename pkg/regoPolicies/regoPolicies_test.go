@@ -0,0 +1,72 @@
+package regoPolicies
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules("./test_fixtures/policies")
+	assert.NoError(t, err)
+
+	var identifiers []string
+	for _, rule := range rules {
+		identifiers = append(identifiers, rule.RuleIdentifier)
+	}
+	assert.Equal(t, []string{"REGO_MAIN_DENY", "REGO_MAIN_WARN", "REGO_NAMING_WARN"}, identifiers)
+
+	assert.Equal(t, "main deny [REGO POLICY]", rules[0].RuleName)
+	assert.Equal(t, "Denied by the rego policies in package main", rules[0].MessageOnFailure)
+	assert.False(t, rules[0].IsWarning)
+	assert.Equal(t, "Warning from the rego policies in package main", rules[1].MessageOnFailure)
+	assert.True(t, rules[1].IsWarning)
+	assert.True(t, rules[2].IsWarning)
+}
+
+func TestLoadRulesValidation(t *testing.T) {
+	rules, err := LoadRules("./test_fixtures/policies")
+	assert.NoError(t, err)
+
+	deploymentContent, err := os.ReadFile("./test_fixtures/deployment.yaml")
+	assert.NoError(t, err)
+	var deployment interface{}
+	assert.NoError(t, yaml.Unmarshal(deploymentContent, &deployment))
+
+	expectedMessages := map[string][]string{
+		"REGO_MAIN_DENY":   {"Deployment frontend-deployment must have an app label", "container web uses the latest tag", "resources must set a namespace"},
+		"REGO_MAIN_WARN":   {"Deployment frontend-deployment has more than 3 replicas"},
+		"REGO_NAMING_WARN": {"names should be at most 10 characters long"},
+	}
+
+	for _, rule := range rules {
+		schemaJson, err := json.Marshal(rule.Schema)
+		assert.NoError(t, err)
+		schema, err := jsonSchemaValidator.Compile(string(schemaJson))
+		assert.NoError(t, err)
+
+		errorsResult, err := jsonSchemaValidator.New().ValidateCompiledSchema(schema, deployment)
+		assert.NoError(t, err)
+
+		var messages []string
+		for _, errorResult := range errorsResult {
+			messages = append(messages, errorResult.Error)
+		}
+		assert.ElementsMatch(t, expectedMessages[rule.RuleIdentifier], messages, rule.RuleIdentifier)
+	}
+}
+
+func TestLoadRulesFailsOnInvalidRego(t *testing.T) {
+	_, err := LoadRules("./test_fixtures/invalid")
+	assert.ErrorContains(t, err, "failed to load rego policies from ./test_fixtures/invalid: 1 error occurred: test_fixtures/invalid/invalid.rego:5: rego_parse_error")
+}
+
+func TestLoadRulesFailsWithoutPolicies(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadRules(dir)
+	assert.EqualError(t, err, "no rego policies found in "+dir)
+}
@@ -0,0 +1,14 @@
+package main
+
+import data.lib.kubernetes
+
+violation[{"msg": msg, "path": "metadata"}] {
+	not input.metadata.namespace
+	msg := "resources must set a namespace"
+}
+
+deny_latest_tag[msg] {
+	container := kubernetes.containers[_]
+	endswith(container.image, ":latest")
+	msg := sprintf("container %s uses the latest tag", [container.name])
+}
@@ -0,0 +1,15 @@
+package main
+
+import data.lib.kubernetes
+
+deny[msg] {
+	kubernetes.is_deployment
+	not input.spec.template.metadata.labels.app
+	msg := sprintf("Deployment %s must have an app label", [input.metadata.name])
+}
+
+warn[msg] {
+	kubernetes.is_deployment
+	input.spec.replicas > 3
+	msg := sprintf("Deployment %s has more than 3 replicas", [input.metadata.name])
+}
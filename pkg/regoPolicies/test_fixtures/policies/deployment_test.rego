@@ -0,0 +1,5 @@
+package main
+
+test_deny_without_app_label {
+	deny["Deployment web must have an app label"] with input as {"kind": "Deployment", "metadata": {"name": "web"}}
+}
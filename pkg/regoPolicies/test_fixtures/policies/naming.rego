@@ -0,0 +1,6 @@
+package naming
+
+warn_name_length[msg] {
+	count(input.metadata.name) > 10
+	msg := "names should be at most 10 characters long"
+}
@@ -0,0 +1,221 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datreeio/datree/pkg/extractor"
+	"github.com/datreeio/datree/pkg/kustomization"
+)
+
+const (
+	EngineHelm      = "helm"
+	EngineKustomize = "kustomize"
+)
+
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// Source is a helm chart or kustomization directory, its files are rendered by its engine instead of being tested as is
+type Source struct {
+	Dir    string
+	Engine string
+}
+
+type CommandRunner interface {
+	ExecuteHelmBin(args []string) ([]byte, error)
+	ExecuteKustomizeBin(args []string) ([]byte, error)
+}
+
+type Renderer struct {
+	commandRunner CommandRunner
+}
+
+func New(commandRunner CommandRunner) *Renderer {
+	return &Renderer{commandRunner: commandRunner}
+}
+
+// FindSources returns the sources that own the files and the files that no source owns.
+// A file is owned by the closest chart or kustomization directory above it, and a subchart is owned by its parent chart.
+// The owner of a file in the working directory is looked for up to the working directory, and the owner of a file outside of it
+// up to the directory that all the files outside of the working directory are in, so the directories above the files that were given aren't searched
+func FindSources(filesPaths []string) ([]Source, []string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	absoluteFilesPaths := make([]string, len(filesPaths))
+	var outsideFilesDirs []string
+	for index, filePath := range filesPaths {
+		absoluteFilesPaths[index], err = filepath.Abs(filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isInDir(absoluteFilesPaths[index], workingDir) {
+			outsideFilesDirs = append(outsideFilesDirs, filepath.Dir(absoluteFilesPaths[index]))
+		}
+	}
+	outsideFilesRootDir := getCommonDir(outsideFilesDirs)
+
+	var sources []Source
+	var unownedFilesPaths []string
+	foundSources := make(map[string]bool)
+	enginesByDir := make(map[string]string)
+	for index, filePath := range filesPaths {
+		rootDir := workingDir
+		if !isInDir(absoluteFilesPaths[index], workingDir) {
+			rootDir = outsideFilesRootDir
+		}
+
+		sourceDir, engine := findOwnerDir(filepath.Dir(absoluteFilesPaths[index]), rootDir, enginesByDir)
+		if sourceDir == "" {
+			unownedFilesPaths = append(unownedFilesPaths, filePath)
+			continue
+		}
+
+		if !foundSources[sourceDir] {
+			foundSources[sourceDir] = true
+			sources = append(sources, Source{Dir: getDisplayPath(sourceDir, workingDir), Engine: engine})
+		}
+	}
+	return sources, unownedFilesPaths, nil
+}
+
+// findOwnerDir looks for the owner from the directory up to the root directory, the root directory is the directory or one above it
+func findOwnerDir(dir string, rootDir string, enginesByDir map[string]string) (string, string) {
+	for {
+		if engine := getCachedDirEngine(dir, enginesByDir); engine == EngineHelm {
+			return getParentChartDir(dir, enginesByDir), engine
+		} else if engine != "" {
+			return dir, engine
+		}
+
+		parentDir := filepath.Dir(dir)
+		if dir == rootDir || parentDir == dir {
+			return "", ""
+		}
+		dir = parentDir
+	}
+}
+
+// getParentChartDir returns the chart that the chart is a subchart of, the parent chart renders its subcharts
+func getParentChartDir(chartDir string, enginesByDir map[string]string) string {
+	for filepath.Base(filepath.Dir(chartDir)) == "charts" {
+		parentChartDir := filepath.Dir(filepath.Dir(chartDir))
+		if getCachedDirEngine(parentChartDir, enginesByDir) != EngineHelm {
+			break
+		}
+		chartDir = parentChartDir
+	}
+	return chartDir
+}
+
+func getCachedDirEngine(dir string, enginesByDir map[string]string) string {
+	engine, ok := enginesByDir[dir]
+	if !ok {
+		engine = getDirEngine(dir)
+		enginesByDir[dir] = engine
+	}
+	return engine
+}
+
+func getDirEngine(dir string) string {
+	if fileExists(filepath.Join(dir, "Chart.yaml")) {
+		return EngineHelm
+	}
+	for _, kustomizationFileName := range kustomizationFileNames {
+		if fileExists(filepath.Join(dir, kustomizationFileName)) {
+			return EngineKustomize
+		}
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	fileInfo, err := os.Stat(path)
+	return err == nil && !fileInfo.IsDir()
+}
+
+// getCommonDir returns the deepest directory that all the directories are in
+func getCommonDir(dirs []string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	commonDir := dirs[0]
+	for _, dir := range dirs[1:] {
+		for !isInDir(dir, commonDir) {
+			commonDir = filepath.Dir(commonDir)
+		}
+	}
+	return commonDir
+}
+
+func isInDir(path string, dir string) bool {
+	relativePath, err := filepath.Rel(dir, path)
+	return err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
+
+// getDisplayPath returns the path relative to the working directory when it's inside of it
+func getDisplayPath(path string, workingDir string) string {
+	if !isInDir(path, workingDir) {
+		return path
+	}
+	relativePath, _ := filepath.Rel(workingDir, path)
+	return relativePath
+}
+
+// Render renders the source with its engine, every rendered resource is labeled with the source by a `# Rendered from:` comment
+func (r *Renderer) Render(source Source) ([]byte, error) {
+	var out []byte
+	var err error
+	switch source.Engine {
+	case EngineHelm:
+		out, err = r.commandRunner.ExecuteHelmBin([]string{source.Dir})
+		if err == nil {
+			out = SetChartSources(out, source.Dir)
+		}
+	case EngineKustomize:
+		out, err = kustomization.BuildWithSources(r.commandRunner, []string{source.Dir}, &kustomization.BuildOptions{})
+	default:
+		err = fmt.Errorf("unknown engine %s", source.Engine)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed rendering %s: %s", source.Dir, err.Error())
+	}
+	return addRenderedFrom(out, source.Dir), nil
+}
+
+// SetChartSources replaces the chart name of the `# Source:` comments of helm with the chart directory, so the results cite the template files
+func SetChartSources(rendered []byte, chartDir string) []byte {
+	lines := strings.Split(string(rendered), "\n")
+	for index, line := range lines {
+		if !strings.HasPrefix(line, extractor.SourceComment) {
+			continue
+		}
+		templatePath := strings.TrimPrefix(line, extractor.SourceComment)
+		if separatorIndex := strings.Index(templatePath, "/"); separatorIndex != -1 {
+			lines[index] = extractor.SourceComment + filepath.Join(chartDir, templatePath[separatorIndex+1:])
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// addRenderedFrom adds a `# Rendered from:` comment at the start of every document
+func addRenderedFrom(rendered []byte, sourceDir string) []byte {
+	renderedFromLine := extractor.RenderedFromComment + sourceDir + "\n"
+	var output bytes.Buffer
+	lines := strings.SplitAfter(string(rendered), "\n")
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) != "---" {
+		output.WriteString(renderedFromLine)
+	}
+	for _, line := range lines {
+		output.WriteString(line)
+		if strings.TrimSpace(line) == "---" {
+			output.WriteString(renderedFromLine)
+		}
+	}
+	return output.Bytes()
+}
@@ -0,0 +1,109 @@
+package renderer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type commandRunnerMock struct {
+	mock.Mock
+}
+
+func (m *commandRunnerMock) ExecuteHelmBin(args []string) ([]byte, error) {
+	arguments := m.Called(args)
+	return arguments.Get(0).([]byte), arguments.Error(1)
+}
+
+func (m *commandRunnerMock) ExecuteKustomizeBin(args []string) ([]byte, error) {
+	arguments := m.Called(args)
+	return arguments.Get(0).([]byte), arguments.Error(1)
+}
+
+func TestFindSources(t *testing.T) {
+	sources, unownedFilesPaths, err := FindSources([]string{
+		"test_fixtures/charts/web/Chart.yaml",
+		"test_fixtures/charts/web/templates/deployment.yaml",
+		"test_fixtures/charts/web/charts/cache/templates/statefulset.yaml",
+		"test_fixtures/kustomize/base/service.yaml",
+		"test_fixtures/kustomize/base/kustomization.yaml",
+		"test_fixtures/manifests/configmap.yaml",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []Source{
+		{Dir: filepath.Join("test_fixtures", "charts", "web"), Engine: EngineHelm},
+		{Dir: filepath.Join("test_fixtures", "kustomize", "base"), Engine: EngineKustomize},
+	}, sources)
+	assert.Equal(t, []string{"test_fixtures/manifests/configmap.yaml"}, unownedFilesPaths)
+}
+
+func TestFindSourcesOutsideOfTheWorkingDirectory(t *testing.T) {
+	// the files are in a chart that isn't theirs, the owners above the files that were given are not looked for
+	rootDir := t.TempDir()
+	chartDir := filepath.Join(rootDir, "team", "chart")
+	manifestPath := filepath.Join(rootDir, "team", "manifests", "configmap.yaml")
+	chartFilesPaths := []string{filepath.Join(chartDir, "Chart.yaml"), filepath.Join(chartDir, "templates", "deployment.yaml")}
+	for _, filePath := range append([]string{filepath.Join(rootDir, "Chart.yaml"), manifestPath}, chartFilesPaths...) {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte("apiVersion: v1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("should look for the owners up to the directory of the files", func(t *testing.T) {
+		sources, unownedFilesPaths, err := FindSources(append([]string{manifestPath}, chartFilesPaths...))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Source{{Dir: chartDir, Engine: EngineHelm}}, sources)
+		assert.Equal(t, []string{manifestPath}, unownedFilesPaths)
+	})
+
+	t.Run("should look for the owner of a single file in its directory", func(t *testing.T) {
+		sources, unownedFilesPaths, err := FindSources([]string{manifestPath})
+
+		assert.Nil(t, err)
+		assert.Empty(t, sources)
+		assert.Equal(t, []string{manifestPath}, unownedFilesPaths)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("should render a chart with the template files as sources", func(t *testing.T) {
+		commandRunner := &commandRunnerMock{}
+		commandRunner.On("ExecuteHelmBin", []string{"test_fixtures/charts/web"}).Return([]byte("---\n# Source: web/templates/deployment.yaml\napiVersion: apps/v1\nkind: Deployment\n---\n# Source: web/charts/cache/templates/statefulset.yaml\napiVersion: apps/v1\nkind: StatefulSet\n"), nil)
+
+		out, err := New(commandRunner).Render(Source{Dir: "test_fixtures/charts/web", Engine: EngineHelm})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "---\n# Rendered from: test_fixtures/charts/web\n# Source: test_fixtures/charts/web/templates/deployment.yaml\napiVersion: apps/v1\nkind: Deployment\n"+
+			"---\n# Rendered from: test_fixtures/charts/web\n# Source: test_fixtures/charts/web/charts/cache/templates/statefulset.yaml\napiVersion: apps/v1\nkind: StatefulSet\n", string(out))
+	})
+
+	t.Run("should render a kustomization with the files of its resources as sources", func(t *testing.T) {
+		commandRunner := &commandRunnerMock{}
+
+		out, err := New(commandRunner).Render(Source{Dir: "test_fixtures/kustomize/base", Engine: EngineKustomize})
+
+		assert.Nil(t, err)
+		workingDir, _ := os.Getwd()
+		assert.Equal(t, "---\n# Rendered from: test_fixtures/kustomize/base\n# Source: "+filepath.Join(workingDir, "test_fixtures/kustomize/base/service.yaml")+"\n"+
+			"apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports:\n    - port: 80\n", string(out))
+		commandRunner.AssertNotCalled(t, "ExecuteKustomizeBin", mock.Anything)
+	})
+
+	t.Run("should return the rendering error of the source", func(t *testing.T) {
+		commandRunner := &commandRunnerMock{}
+		commandRunner.On("ExecuteHelmBin", mock.Anything).Return([]byte(nil), errors.New("helm is not installed"))
+
+		_, err := New(commandRunner).Render(Source{Dir: "test_fixtures/charts/web", Engine: EngineHelm})
+
+		assert.EqualError(t, err, "failed rendering test_fixtures/charts/web: helm is not installed")
+	})
+}
@@ -4,8 +4,14 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v2"
 	"github.com/datreeio/datree/pkg/defaultPolicies"
 
+	"github.com/datreeio/datree/pkg/admissionPolicies"
+	"github.com/datreeio/datree/pkg/assertions"
 	"github.com/datreeio/datree/pkg/defaultRules"
 	"github.com/datreeio/datree/pkg/jsonSchemaValidator"
 	"github.com/ghodss/yaml"
@@ -16,6 +22,14 @@ import (
 var policiesSchemaContent string
 
 func ValidatePoliciesYaml(content []byte, policyYamlPath string) error {
+	errorPrefix := fmt.Errorf("found errors in policies file %s:", policyYamlPath)
+
+	// admission policies manifests are validated as the custom rules they are converted to
+	content, err := admissionPolicies.MergeIntoPoliciesYaml(content)
+	if err != nil {
+		return fmt.Errorf("%s\n%s", errorPrefix, err)
+	}
+
 	jsonSchemaValidator := jsonSchemaValidator.New()
 	jsonContent, _ := yaml.YAMLToJSON(content)
 	errorsResult, err := jsonSchemaValidator.Validate(policiesSchemaContent, jsonContent)
@@ -24,7 +38,6 @@ func ValidatePoliciesYaml(content []byte, policyYamlPath string) error {
 		return err
 	}
 
-	errorPrefix := fmt.Errorf("found errors in policies file %s:", policyYamlPath)
 	if errorsResult != nil {
 		validationErrors := errorPrefix
 
@@ -62,11 +75,204 @@ func validatePoliciesContent(content []byte) error {
 		return err
 	}
 
+	// validate the extends, disable and messageOverrides fields of the policies
+	err = validatePoliciesInheritance(schema.Policies)
+	if err != nil {
+		return err
+	}
+
+	// validate the match and exclude blocks of the policies rules
+	err = validateRuleSelectors(schema.Policies)
+	if err != nil {
+		return err
+	}
+
+	// validate the params of the policies rules against the parameters of the default rules
+	err = validateRulesParams(schema.Policies)
+	if err != nil {
+		return err
+	}
+
 	// validate the schema of each rule
 	err = validateSchemaField(schema.CustomRules)
 	return err
 }
 
+func validatePoliciesInheritance(policies []*defaultPolicies.Policy) error {
+	policiesIndexes := make(map[string]int)
+	for index, policy := range policies {
+		policiesIndexes[policy.Name] = index
+	}
+
+	for index, policy := range policies {
+		for extendsIndex, parentName := range policy.Extends {
+			if _, ok := policiesIndexes[parentName]; !ok {
+				return fmt.Errorf("(root)/policies/%d/extends/%d: policy \"%s\" doesn't exist", index, extendsIndex, parentName)
+			}
+		}
+	}
+
+	for index := range policies {
+		cycle := findInheritanceCycle(policies, policiesIndexes, index, []string{})
+		if cycle != nil {
+			return fmt.Errorf("(root)/policies/%d/extends: policies inheritance cycle: %s", index, strings.Join(cycle, " -> "))
+		}
+	}
+
+	for index, policy := range policies {
+		identifiers := getInheritedIdentifiers(policies, policiesIndexes, index)
+		for disableIndex, identifier := range policy.Disable {
+			if !identifiers[identifier] {
+				return fmt.Errorf("(root)/policies/%d/disable/%d: rule \"%s\" is not in the policy or in the policies it extends", index, disableIndex, identifier)
+			}
+		}
+		for identifier := range policy.MessageOverrides {
+			if !identifiers[identifier] {
+				return fmt.Errorf("(root)/policies/%d/messageOverrides/%s: rule \"%s\" is not in the policy or in the policies it extends", index, identifier, identifier)
+			}
+		}
+	}
+	return nil
+}
+
+// findInheritanceCycle returns the names of the policies in the cycle, starting and ending with the same policy, or nil when there's no cycle
+func findInheritanceCycle(policies []*defaultPolicies.Policy, policiesIndexes map[string]int, index int, inheritancePath []string) []string {
+	policy := policies[index]
+	for pathIndex, name := range inheritancePath {
+		if name == policy.Name {
+			return append(inheritancePath[pathIndex:], policy.Name)
+		}
+	}
+
+	inheritancePath = append(inheritancePath, policy.Name)
+	for _, parentName := range policy.Extends {
+		cycle := findInheritanceCycle(policies, policiesIndexes, policiesIndexes[parentName], inheritancePath)
+		if cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// getInheritedIdentifiers returns the identifiers of the rules of the policy and of the policies it extends, the policies must not have cycles
+func getInheritedIdentifiers(policies []*defaultPolicies.Policy, policiesIndexes map[string]int, index int) map[string]bool {
+	identifiers := make(map[string]bool)
+	for _, rule := range policies[index].Rules {
+		identifiers[rule.Identifier] = true
+	}
+	for _, parentName := range policies[index].Extends {
+		for identifier := range getInheritedIdentifiers(policies, policiesIndexes, policiesIndexes[parentName]) {
+			identifiers[identifier] = true
+		}
+	}
+	return identifiers
+}
+
+func validateRuleSelectors(policies []*defaultPolicies.Policy) error {
+	for policyIndex, policy := range policies {
+		for ruleIndex, rule := range policy.Rules {
+			selectors := map[string]*defaultPolicies.RuleSelector{"match": rule.Match, "exclude": rule.Exclude}
+			for _, selectorKey := range []string{"match", "exclude"} {
+				selector := selectors[selectorKey]
+				if selector == nil {
+					continue
+				}
+				err := validateRuleSelector(selector)
+				if err != nil {
+					return fmt.Errorf("(root)/policies/%d/rules/%d/%s%s", policyIndex, ruleIndex, selectorKey, err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateRulesParams(policies []*defaultPolicies.Policy) error {
+	defaultRulesDefinitions, err := defaultRules.GetDefaultRules()
+	if err != nil {
+		return err
+	}
+
+	for policyIndex, policy := range policies {
+		for ruleIndex, rule := range policy.Rules {
+			if len(rule.Params) == 0 {
+				continue
+			}
+
+			var defaultRule *defaultRules.DefaultRuleDefinition
+			for _, definition := range defaultRulesDefinitions.Rules {
+				if definition.UniqueName == rule.Identifier {
+					defaultRule = definition
+					break
+				}
+			}
+
+			paramsNames := make([]string, 0, len(rule.Params))
+			for name := range rule.Params {
+				paramsNames = append(paramsNames, name)
+			}
+			sort.Strings(paramsNames)
+
+			for _, name := range paramsNames {
+				var parameter *defaultRules.RuleParameter
+				if defaultRule != nil {
+					parameter = defaultRule.GetParameter(name)
+				}
+				if parameter == nil {
+					return fmt.Errorf("(root)/policies/%d/rules/%d/params/%s: rule %s has no parameter %s", policyIndex, ruleIndex, name, rule.Identifier, name)
+				}
+				if err := parameter.ValidateValue(rule.Params[name]); err != nil {
+					return fmt.Errorf("(root)/policies/%d/rules/%d/params/%s: %s", policyIndex, ruleIndex, name, err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateRuleSelector returns an error that starts with the path of the invalid field in the selector
+func validateRuleSelector(selector *defaultPolicies.RuleSelector) error {
+	globsFields := map[string][]string{"namespaces": selector.Namespaces, "files": selector.Files}
+	for _, field := range []string{"namespaces", "files"} {
+		for index, glob := range globsFields[field] {
+			if err := validateGlob(glob); err != nil {
+				return fmt.Errorf("/%s/%d: invalid glob pattern \"%s\"", field, index, glob)
+			}
+		}
+	}
+
+	labelSelectors := map[string]*defaultPolicies.LabelSelector{"labels": selector.Labels, "annotations": selector.Annotations}
+	for _, field := range []string{"labels", "annotations"} {
+		labelSelector := labelSelectors[field]
+		if labelSelector == nil {
+			continue
+		}
+		for index, requirement := range labelSelector.MatchExpressions {
+			switch requirement.Operator {
+			case "In", "NotIn":
+				if len(requirement.Values) == 0 {
+					return fmt.Errorf("/%s/matchExpressions/%d: values must be non-empty for operator %s", field, index, requirement.Operator)
+				}
+			case "Exists", "DoesNotExist":
+				if len(requirement.Values) > 0 {
+					return fmt.Errorf("/%s/matchExpressions/%d: values must be empty for operator %s", field, index, requirement.Operator)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateGlob validates the glob with the library the rule selectors are matched with.
+// doublestar only parses the pattern as far as the name matches it, so the glob is also matched against itself
+func validateGlob(glob string) error {
+	if _, err := doublestar.Match(glob, ""); err != nil {
+		return err
+	}
+	_, err := doublestar.Match(glob, glob)
+	return err
+}
+
 func validateIdentifier(policies []*defaultPolicies.Policy, customRules []*defaultPolicies.CustomRule) error {
 
 	err := checkIdentifierInPolicy(policies, customRules)
@@ -191,11 +397,28 @@ func validateSchemaField(customRules []*defaultPolicies.CustomRule) error {
 	for index, rule := range customRules {
 		var err error
 		var jsonContent string
-		if rule.Schema != nil && rule.JsonSchema != "" {
-			return fmt.Errorf("(root)/customRules/%d: Exactly one of [schema,jsonSchema] should be defined per custom rule", index)
+		definedSchemasCount := 0
+		for _, isDefined := range []bool{rule.Schema != nil, rule.JsonSchema != "", len(rule.Assertions) > 0} {
+			if isDefined {
+				definedSchemasCount++
+			}
+		}
+		if definedSchemasCount != 1 {
+			return fmt.Errorf("(root)/customRules/%d: Exactly one of [schema,jsonSchema,assertions] should be defined per custom rule", index)
 		}
 		var schemaKeyUsed string
-		if rule.Schema != nil {
+		if len(rule.Assertions) > 0 {
+			for assertionIndex, assertion := range rule.Assertions {
+				_, err = assertions.CompileAssertion(assertion)
+				if err != nil {
+					return fmt.Errorf("(root)/customRules/%d/assertions/%d: %s", index, assertionIndex, err.Error())
+				}
+			}
+			schema, _ := assertions.Compile(rule.Assertions)
+			content, _ := json.Marshal(schema)
+			jsonContent = string(content)
+			schemaKeyUsed = "assertions"
+		} else if rule.Schema != nil {
 			var content []byte
 			schema := rule.Schema
 			content, err = json.Marshal(schema)
@@ -209,14 +432,17 @@ func validateSchemaField(customRules []*defaultPolicies.CustomRule) error {
 			schemaKeyUsed = "jsonSchema"
 		}
 
-		if jsonContent == "" {
-			return fmt.Errorf("(root)/customRules/%d: Exactly one of [schema,jsonSchema] should be defined per custom rule", index)
-		}
 		schemaLoader := gojsonschema.NewStringLoader(jsonContent)
 		_, err = gojsonschema.NewSchemaLoader().Compile(schemaLoader)
 		if err != nil {
 			return fmt.Errorf("(root)/customRules/%v/%s: %s", index, schemaKeyUsed, err.Error())
 		}
+
+		// compile with datree's custom keys as well, so invalid rego and CEL code is rejected when the policies file loads
+		_, err = jsonSchemaValidator.Compile(jsonContent)
+		if err != nil {
+			return fmt.Errorf("(root)/customRules/%v/%s: %s", index, schemaKeyUsed, err.Error())
+		}
 	}
 	return nil
 }
@@ -74,6 +74,66 @@ var customRuleIdentifierMatchDefaultRule string
 //go:embed test_fixtures/duplicateRuleIdentifier.yaml
 var duplicateRuleIdentifier string
 
+//go:embed test_fixtures/customRuleInvalidRegoCode.yaml
+var customRuleInvalidRegoCode string
+
+//go:embed test_fixtures/validatingAdmissionPolicy.yaml
+var validatingAdmissionPolicyValid string
+
+//go:embed test_fixtures/validatingAdmissionPolicyInvalidExpression.yaml
+var validatingAdmissionPolicyInvalidExpression string
+
+//go:embed test_fixtures/ruleSelectorValid.yaml
+var ruleSelectorValid string
+
+//go:embed test_fixtures/ruleSelectorInvalidOperator.yaml
+var ruleSelectorInvalidOperator string
+
+//go:embed test_fixtures/ruleSelectorMissingValues.yaml
+var ruleSelectorMissingValues string
+
+//go:embed test_fixtures/ruleSelectorInvalidGlob.yaml
+var ruleSelectorInvalidGlob string
+
+//go:embed test_fixtures/ruleSelectorInvalidBraceGlob.yaml
+var ruleSelectorInvalidBraceGlob string
+
+//go:embed test_fixtures/ruleSeverityValid.yaml
+var ruleSeverityValid string
+
+//go:embed test_fixtures/ruleSeverityInvalid.yaml
+var ruleSeverityInvalid string
+
+//go:embed test_fixtures/policyExtendsValid.yaml
+var policyExtendsValid string
+
+//go:embed test_fixtures/policyExtendsMissingPolicy.yaml
+var policyExtendsMissingPolicy string
+
+//go:embed test_fixtures/policyExtendsCycle.yaml
+var policyExtendsCycle string
+
+//go:embed test_fixtures/policyDisableUnknownRule.yaml
+var policyDisableUnknownRule string
+
+//go:embed test_fixtures/ruleParamsValid.yaml
+var ruleParamsValid string
+
+//go:embed test_fixtures/ruleParamsUnknownParameter.yaml
+var ruleParamsUnknownParameter string
+
+//go:embed test_fixtures/ruleParamsInvalidType.yaml
+var ruleParamsInvalidType string
+
+//go:embed test_fixtures/customRuleAssertionsValid.yaml
+var customRuleAssertionsValid string
+
+//go:embed test_fixtures/customRuleAssertionsInvalidOp.yaml
+var customRuleAssertionsInvalidOp string
+
+//go:embed test_fixtures/customRuleAssertionsInvalidValue.yaml
+var customRuleAssertionsInvalidValue string
+
 func assertValidationResult(t *testing.T, policiesFile string, policiesFilePath string, expectedError error) {
 	err := ValidatePoliciesYaml([]byte(policiesFile), policiesFilePath)
 	assert.Equal(t, err, expectedError)
@@ -86,7 +146,7 @@ func TestValidatePoliciesYaml(t *testing.T) {
 	assertValidationResult(t, missingPolicyRules, "./test_fixtures/missingPolicyRules.yaml", nil)
 	assertValidationResult(t, missingApiVersion, "./test_fixtures/missingApiVersion.yaml", errors.New("found errors in policies file ./test_fixtures/missingApiVersion.yaml:\n(root): missing properties: 'apiVersion'"))
 	assertValidationResult(t, missingPolicyName, "./test_fixtures/missingPolicyName.yaml", errors.New("found errors in policies file ./test_fixtures/missingPolicyName.yaml:\n(root)/policies/0: missing properties: 'name'"))
-	assertValidationResult(t, missingSchema, "./test_fixtures/missingSchema.yaml", errors.New("found errors in policies file ./test_fixtures/missingSchema.yaml:\n(root)/customRules/1: Exactly one of [schema,jsonSchema] should be defined per custom rule"))
+	assertValidationResult(t, missingSchema, "./test_fixtures/missingSchema.yaml", errors.New("found errors in policies file ./test_fixtures/missingSchema.yaml:\n(root)/customRules/1: Exactly one of [schema,jsonSchema,assertions] should be defined per custom rule"))
 	assertValidationResult(t, multipleDefaultPolicy, "./test_fixtures/multipleDefaultPolicy.yaml", errors.New("found errors in policies file ./test_fixtures/multipleDefaultPolicy.yaml:\n(root)/policies: Should have exactly one policy set as default"))
 	assertValidationResult(t, noDefaultPolicy, "./test_fixtures/noDefaultPolicy.yaml", errors.New("found errors in policies file ./test_fixtures/noDefaultPolicy.yaml:\n(root)/policies: Should have exactly one policy set as default"))
 	assertValidationResult(t, wrongApiVersion, "./test_fixtures/wrongApiVersion.yaml", errors.New("found errors in policies file ./test_fixtures/wrongApiVersion.yaml:\n(root)/apiVersion: value must be \"v1\""))
@@ -98,10 +158,42 @@ func TestValidatePoliciesYaml(t *testing.T) {
 	assertValidationResult(t, customRuleValidSchema, "./test_fixtures/customRuleValidSchema.yaml", nil)
 	assertValidationResult(t, customRuleInvalidSchema, "./test_fixtures/customRuleInvalidSchema.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleInvalidSchema.yaml:\n(root)/customRules/1/schema: has a primitive type that is NOT VALID -- given: /arrayi/ Expected valid values are:[array boolean integer number null object string]"))
 	assertValidationResult(t, customRuleInvalidJsonSchema, "./test_fixtures/customRuleInvalidJsonSchema.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleInvalidJsonSchema.yaml:\n(root)/customRules/1/jsonSchema: Invalid type. Expected: array of schemas, given: definitions"))
-	assertValidationResult(t, bothSchemaAndJsonSchemaDefined, "./test_fixtures/bothSchemaAndJsonSchemaDefined.yaml", errors.New("found errors in policies file ./test_fixtures/bothSchemaAndJsonSchemaDefined.yaml:\n(root)/customRules/0: Exactly one of [schema,jsonSchema] should be defined per custom rule"))
+	assertValidationResult(t, bothSchemaAndJsonSchemaDefined, "./test_fixtures/bothSchemaAndJsonSchemaDefined.yaml", errors.New("found errors in policies file ./test_fixtures/bothSchemaAndJsonSchemaDefined.yaml:\n(root)/customRules/0: Exactly one of [schema,jsonSchema,assertions] should be defined per custom rule"))
 	assertValidationResult(t, identifierNotDefined, "./test_fixtures/identifierNotDefined.yaml", errors.New("found errors in policies file ./test_fixtures/identifierNotDefined.yaml:\n(root)/policies/0/rules: identifier \"SOME_IDENTIFIER_NAME\" is neither custom nor default"))
 	assertValidationResult(t, customRuleIdentifierNotUnique, "./test_fixtures/customRuleIdentifierNotUnique.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleIdentifierNotUnique.yaml:\n(root)/customRules: identifier \"PODDISRUPTIONBUDGET_DENY_ZERO_VOLUNTARY_DISRUPTION\" is used in more than one custom rule"))
 	assertValidationResult(t, customRuleIdentifierMatchDefaultRule, "./test_fixtures/customRuleIdentifierMatchDefaultRule.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleIdentifierMatchDefaultRule.yaml:\n(root)/customRules/0: a default rule with same identifier \"RESOURCE_MISSING_NAME\" already exists"))
 	assertValidationResult(t, duplicateRuleIdentifier, "./test_fixtures/duplicateRuleIdentifier.yaml", errors.New("found errors in policies file ./test_fixtures/duplicateRuleIdentifier.yaml:\n(root)/policies/0/rules: identifier \"PODDISRUPTIONBUDGET_DENY_ZERO_VOLUNTARY_DISRUPTION\" is used more than once in policy"))
 	assertValidationResult(t, customRuleJsonSchemaInvalidJson, "./test_fixtures/customRuleJsonSchemaInvalidJson.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleJsonSchemaInvalidJson.yaml:\n(root)/customRules/1/jsonSchema: invalid character '2' looking for beginning of object key string"))
+	assertValidationResult(t, customRuleInvalidRegoCode, "./test_fixtures/customRuleInvalidRegoCode.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleInvalidRegoCode.yaml:\n(root)/customRules/0/schema: can't compile rego code, 1 error occurred: main.rego:4: rego_type_error: undefined function not_a_function"))
+
+	// validatingAdmissionPolicy
+	assertValidationResult(t, validatingAdmissionPolicyValid, "./test_fixtures/validatingAdmissionPolicy.yaml", nil)
+	assertValidationResult(t, validatingAdmissionPolicyInvalidExpression, "./test_fixtures/validatingAdmissionPolicyInvalidExpression.yaml", errors.New("found errors in policies file ./test_fixtures/validatingAdmissionPolicyInvalidExpression.yaml:\n(root)/customRules/0/schema: cel expression compile error: ERROR: <input>:1:28: undeclared reference to 'hostNetworkAllowed' (in container '')\n | object.spec.hostNetwork == hostNetworkAllowed\n | ...........................^"))
+
+	// rule selectors
+	assertValidationResult(t, ruleSelectorValid, "./test_fixtures/ruleSelectorValid.yaml", nil)
+	assertValidationResult(t, ruleSelectorInvalidOperator, "./test_fixtures/ruleSelectorInvalidOperator.yaml", errors.New("found errors in policies file ./test_fixtures/ruleSelectorInvalidOperator.yaml:\n(root)/policies/0/rules/0/match/labels/matchExpressions/0/operator: value must be one of \"In\", \"NotIn\", \"Exists\", \"DoesNotExist\""))
+	assertValidationResult(t, ruleSelectorMissingValues, "./test_fixtures/ruleSelectorMissingValues.yaml", errors.New("found errors in policies file ./test_fixtures/ruleSelectorMissingValues.yaml:\n(root)/policies/0/rules/0/exclude/labels/matchExpressions/0: values must be non-empty for operator NotIn"))
+	assertValidationResult(t, ruleSelectorInvalidGlob, "./test_fixtures/ruleSelectorInvalidGlob.yaml", errors.New("found errors in policies file ./test_fixtures/ruleSelectorInvalidGlob.yaml:\n(root)/policies/0/rules/0/match/files/0: invalid glob pattern \"manifests/[a-\""))
+	assertValidationResult(t, ruleSelectorInvalidBraceGlob, "./test_fixtures/ruleSelectorInvalidBraceGlob.yaml", errors.New("found errors in policies file ./test_fixtures/ruleSelectorInvalidBraceGlob.yaml:\n(root)/policies/0/rules/0/exclude/namespaces/0: invalid glob pattern \"kube-{system,public\""))
+
+	// rule severity
+	assertValidationResult(t, ruleSeverityValid, "./test_fixtures/ruleSeverityValid.yaml", nil)
+	assertValidationResult(t, ruleSeverityInvalid, "./test_fixtures/ruleSeverityInvalid.yaml", errors.New("found errors in policies file ./test_fixtures/ruleSeverityInvalid.yaml:\n(root)/policies/0/rules/0/severity: value must be one of \"critical\", \"high\", \"medium\", \"low\", \"info\""))
+
+	// rule params
+	assertValidationResult(t, ruleParamsValid, "./test_fixtures/ruleParamsValid.yaml", nil)
+	assertValidationResult(t, ruleParamsUnknownParameter, "./test_fixtures/ruleParamsUnknownParameter.yaml", errors.New("found errors in policies file ./test_fixtures/ruleParamsUnknownParameter.yaml:\n(root)/policies/0/rules/0/params/maxReplicas: rule DEPLOYMENT_INCORRECT_REPLICAS_VALUE has no parameter maxReplicas"))
+	assertValidationResult(t, ruleParamsInvalidType, "./test_fixtures/ruleParamsInvalidType.yaml", errors.New("found errors in policies file ./test_fixtures/ruleParamsInvalidType.yaml:\n(root)/policies/0/rules/0/params/minReplicas: parameter minReplicas must be of type integer, got three"))
+
+	// custom rules assertions
+	assertValidationResult(t, customRuleAssertionsValid, "./test_fixtures/customRuleAssertionsValid.yaml", nil)
+	assertValidationResult(t, customRuleAssertionsInvalidOp, "./test_fixtures/customRuleAssertionsInvalidOp.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleAssertionsInvalidOp.yaml:\n(root)/customRules/0/assertions/0/op: value must be one of \"exists\", \"notExists\", \"eq\", \"in\", \"matches\", \"gte\", \"lte\", \"quantityGte\", \"quantityLte\""))
+	assertValidationResult(t, customRuleAssertionsInvalidValue, "./test_fixtures/customRuleAssertionsInvalidValue.yaml", errors.New("found errors in policies file ./test_fixtures/customRuleAssertionsInvalidValue.yaml:\n(root)/customRules/0/assertions/1: op quantityLte: invalid quantity \"two gigabytes\""))
+
+	// policy inheritance
+	assertValidationResult(t, policyExtendsValid, "./test_fixtures/policyExtendsValid.yaml", nil)
+	assertValidationResult(t, policyExtendsMissingPolicy, "./test_fixtures/policyExtendsMissingPolicy.yaml", errors.New("found errors in policies file ./test_fixtures/policyExtendsMissingPolicy.yaml:\n(root)/policies/0/extends/0: policy \"Base\" doesn't exist"))
+	assertValidationResult(t, policyExtendsCycle, "./test_fixtures/policyExtendsCycle.yaml", errors.New("found errors in policies file ./test_fixtures/policyExtendsCycle.yaml:\n(root)/policies/0/extends: policies inheritance cycle: Base -> Production -> Base"))
+	assertValidationResult(t, policyDisableUnknownRule, "./test_fixtures/policyDisableUnknownRule.yaml", errors.New("found errors in policies file ./test_fixtures/policyDisableUnknownRule.yaml:\n(root)/policies/1/disable/0: rule \"CONTAINERS_MISSING_MEMORY_LIMIT_KEY\" is not in the policy or in the policies it extends"))
 }